@@ -6,9 +6,12 @@ import (
 	"ashno-onepay/internal/jwt"
 	"ashno-onepay/internal/log"
 	middleware "ashno-onepay/internal/middleware"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/onepay"
 	"ashno-onepay/internal/repository"
 	"ashno-onepay/internal/server"
 	"ashno-onepay/internal/service"
+	"context"
 	"runtime"
 	"time"
 )
@@ -35,16 +38,74 @@ func main() {
 	//repo
 	registrationRepo := repository.GetRegistrationRepositoryInstance(config.GetDB())
 	registrationOptionsRepo := repository.GetRegistrationOptionRepositoryInstance(config.GetDB())
+	paymentTransactionRepo := repository.GetPaymentTransactionRepositoryInstance(config.GetDB())
+	refundRepo := repository.GetRefundRepositoryInstance(config.GetDB())
+	exchangeRateRepo := repository.GetExchangeRateRepositoryInstance(config.GetDB())
+	periodRepo := repository.GetPeriodRepositoryInstance(config.GetDB())
+	addOnRepo := repository.GetAddOnRepositoryInstance(config.GetDB())
+	promoCodeRepo := repository.GetPromoCodeRepositoryInstance(config.GetDB())
+	registrationGroupRepo := repository.GetRegistrationGroupRepositoryInstance(config.GetDB())
+	registrationChangeRepo := repository.GetRegistrationChangeRepositoryInstance(config.GetDB())
+	auditLogRepo := repository.GetAuditLogRepositoryInstance(config.GetDB())
+	categoryCapacityRepo := repository.GetCategoryCapacityRepositoryInstance(config.GetDB())
+	waitlistRepo := repository.GetWaitlistRepositoryInstance(config.GetDB())
+	adminUserRepo := repository.GetAdminUserRepositoryInstance(config.GetDB())
+	sessionRepo := repository.GetSessionRepositoryInstance(config.GetDB())
+	//onepay
+	onePayClients := map[model.PaymentMethod]*onepay.Client{
+		model.PaymentMethodOnePayDomestic: onepay.NewClient(cfg.OnePay),
+	}
+	// without an international merchant, foreign attendees pay through the domestic one
+	if cfg.OnePayInternational.IsConfigured() {
+		onePayClients[model.PaymentMethodOnePayInternational] = onepay.NewClient(cfg.OnePayInternational)
+	} else {
+		logger.Warn("ONE_PAY_USD_* is not configured, international payments go through the domestic merchant")
+	}
 	//service
-	registrationSvc := service.GetRegistrationServiceInstance(registrationRepo, registrationOptionsRepo, &cfg)
+	rateSvc := service.GetRateServiceInstance(exchangeRateRepo, service.NewRateProvider(cfg.Rate))
+	periodSvc := service.GetPeriodServiceInstance(periodRepo)
+	addOnSvc := service.GetAddOnServiceInstance(addOnRepo, &cfg)
+	promoCodeSvc := service.GetPromoCodeServiceInstance(promoCodeRepo, &cfg)
+	registrationOptionSvc := service.GetRegistrationOptionServiceInstance(registrationOptionsRepo, categoryCapacityRepo, periodSvc, addOnSvc)
+	adminUserSvc := service.GetAdminUserServiceInstance(adminUserRepo, sessionRepo, jwt.NewIssuer(cfg.Server.JwtKey), &cfg)
+	registrationSvc := service.GetRegistrationServiceInstance(registrationRepo, registrationOptionsRepo, paymentTransactionRepo, refundRepo, registrationGroupRepo, registrationChangeRepo, auditLogRepo, categoryCapacityRepo, waitlistRepo, onePayClients, rateSvc, periodSvc, addOnSvc, promoCodeSvc, &cfg)
 	//controller
 	registrationCtrl := controller.NewRegistrationController(registrationSvc, &cfg)
+	exchangeRateCtrl := controller.NewExchangeRateController(rateSvc)
+	periodCtrl := controller.NewPeriodController(periodSvc)
+	registrationOptionCtrl := controller.NewRegistrationOptionController(registrationOptionSvc)
+	addOnCtrl := controller.NewAddOnController(addOnSvc)
+	promoCodeCtrl := controller.NewPromoCodeController(promoCodeSvc)
+	onePayReturnCtrl := controller.NewOnePayReturnController(
+		registrationSvc, jwt.NewIssuer(cfg.Server.JwtKey), jwt.NewValidator(cfg.Server.JwtKey), &cfg,
+	)
+	manageCtrl := controller.NewManageController(
+		registrationSvc, jwt.NewIssuer(cfg.Server.JwtKey), jwt.NewValidator(cfg.Server.JwtKey), &cfg,
+	)
+	adminUserCtrl := controller.NewAdminUserController(adminUserSvc)
 
-	sessionMiddleware := middleware.NewSessionMiddleware(jwt.NewValidator(cfg.Server.JwtKey))
+	if err := adminUserSvc.Bootstrap(); err != nil {
+		logger.WithError(err).Error("failed to create the bootstrap admin user")
+	}
+
+	if cfg.Reconcile.Enabled {
+		go service.NewPaymentReconciler(registrationSvc, &cfg).Run(context.Background())
+	}
+	go service.NewWaitlistInviter(registrationSvc, &cfg).Run(context.Background())
+
+	sessionMiddleware := middleware.NewSessionMiddleware(jwt.NewValidator(cfg.Server.JwtKey), adminUserSvc)
 
 	sv := server.NewServer(
 		logger, &cfg, http,
 		registrationCtrl,
+		exchangeRateCtrl,
+		onePayReturnCtrl,
+		periodCtrl,
+		registrationOptionCtrl,
+		addOnCtrl,
+		promoCodeCtrl,
+		manageCtrl,
+		adminUserCtrl,
 		sessionMiddleware)
 	sv.Run()
 
@@ -0,0 +1,269 @@
+// Command onepay-sim is a local stand-in for the OnePay paygate. It accepts the signed
+// redirect built by the registration service, lets a developer pick the outcome, then
+// sends a signed IPN to the callback URL and redirects the browser to vpc_ReturnURL.
+// QueryDR and refund calls are answered from the payments made through the simulator.
+package main
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/onepay"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type simulator struct {
+	config     config.OnePay
+	ipnURL     string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	payments map[string]map[string]string // signed results by vpc_MerchTxnRef
+}
+
+var outcomes = []struct {
+	Code  string
+	Label string
+}{
+	{onepay.ResponseCodeSuccess, "Success"},
+	{onepay.ResponseCodeBankDeclined, "Declined by bank"},
+	{onepay.ResponseCodeInsufficientFunds, "Insufficient funds"},
+	{onepay.ResponseCodeInvalidOTP, "Wrong OTP"},
+	{onepay.ResponseCodeCancelled, "Cancel"},
+}
+
+var paygatePage = template.Must(template.New("paygate").Parse(`<!DOCTYPE html>
+<html>
+<head><title>OnePay simulator</title></head>
+<body style="font-family: sans-serif; max-width: 480px; margin: 40px auto">
+<h2>OnePay simulator</h2>
+<table>
+<tr><td>Merchant</td><td>{{.Params.vpc_Merchant}}</td></tr>
+<tr><td>Order</td><td>{{.Params.vpc_OrderInfo}}</td></tr>
+<tr><td>Txn ref</td><td>{{.Params.vpc_MerchTxnRef}}</td></tr>
+<tr><td>Amount</td><td>{{.Amount}} {{.Params.vpc_Currency}}</td></tr>
+</table>
+<form method="post" action="/paygate/result">
+<input type="hidden" name="query" value="{{.Query}}">
+{{range .Outcomes}}<p><button name="code" value="{{.Code}}">{{.Label}}</button></p>
+{{end}}
+</form>
+</body>
+</html>`))
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	ipnURL := flag.String("ipn", "http://localhost:8081/onepay/ipn", "IPN URL used when the request has no absolute vpc_CallbackURL")
+	flag.Parse()
+
+	sim := &simulator{
+		config:     config.GetConfig().OnePay,
+		ipnURL:     *ipnURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		payments:   map[string]map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/paygate/vpcpay.op", sim.handlePaygate)
+	mux.HandleFunc("/paygate/result", sim.handleResult)
+	mux.HandleFunc("/msp/api/v1/vpc/invoices/queries", sim.handleQueryDR)
+	mux.HandleFunc("/msp/api/v1/vpc/refunds", sim.handleRefund)
+
+	log.Printf("OnePay simulator listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func (s *simulator) handlePaygate(w http.ResponseWriter, r *http.Request) {
+	params := flatten(r.URL.Query())
+	if err := s.checkMerchant(params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	amount, _ := strconv.ParseInt(params["vpc_Amount"], 10, 64)
+	err := paygatePage.Execute(w, map[string]interface{}{
+		"Params":   params,
+		"Amount":   amount / 100,
+		"Query":    r.URL.RawQuery,
+		"Outcomes": outcomes,
+	})
+	if err != nil {
+		log.Printf("render paygate page: %v", err)
+	}
+}
+
+func (s *simulator) handleResult(w http.ResponseWriter, r *http.Request) {
+	query, err := url.ParseQuery(r.PostFormValue("query"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request := flatten(query)
+	if err := s.checkMerchant(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	code := r.PostFormValue("code")
+
+	result := map[string]string{
+		"vpc_Command":         "pay",
+		"vpc_Locale":          request["vpc_Locale"],
+		"vpc_Currency":        request["vpc_Currency"],
+		"vpc_Merchant":        request["vpc_Merchant"],
+		"vpc_MerchTxnRef":     request["vpc_MerchTxnRef"],
+		"vpc_OrderInfo":       request["vpc_OrderInfo"],
+		"vpc_Amount":          request["vpc_Amount"],
+		"vpc_TxnResponseCode": code,
+		"vpc_TransactionNo":   strconv.FormatInt(time.Now().UnixNano(), 10),
+		"vpc_Message":         onepay.Describe(code),
+	}
+	signed, err := s.sign(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.mu.Lock()
+	s.payments[result["vpc_MerchTxnRef"]] = result
+	s.mu.Unlock()
+
+	callbackURL := request["vpc_CallbackURL"]
+	if !strings.HasPrefix(callbackURL, "http://") && !strings.HasPrefix(callbackURL, "https://") {
+		callbackURL = s.ipnURL
+	}
+	s.sendIPN(callbackURL, signed)
+
+	http.Redirect(w, r, appendQuery(request["vpc_ReturnURL"], signed), http.StatusFound)
+}
+
+func (s *simulator) handleQueryDR(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.parseMerchantAPI(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	result, found := s.payments[request["vpc_MerchTxnRef"]]
+	s.mu.Unlock()
+	if !found {
+		fmt.Fprint(w, url.Values{
+			"vpc_DRExists":    {"N"},
+			"vpc_MerchTxnRef": {request["vpc_MerchTxnRef"]},
+		}.Encode())
+		return
+	}
+	response := map[string]string{"vpc_DRExists": "Y"}
+	for k, v := range result {
+		response[k] = v
+	}
+	signed, err := s.sign(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, signed.Encode())
+}
+
+func (s *simulator) handleRefund(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.parseMerchantAPI(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	payment, found := s.payments[request["vpc_OrgMerchTxnRef"]]
+	s.mu.Unlock()
+	code := onepay.ResponseCodeSuccess
+	if !found || payment["vpc_TxnResponseCode"] != onepay.ResponseCodeSuccess {
+		code = onepay.ResponseCodeUnspecifiedFailure
+	}
+	signed, err := s.sign(map[string]string{
+		"vpc_Command":         "refund",
+		"vpc_Merchant":        request["vpc_Merchant"],
+		"vpc_MerchTxnRef":     request["vpc_MerchTxnRef"],
+		"vpc_Amount":          request["vpc_Amount"],
+		"vpc_TxnResponseCode": code,
+		"vpc_Message":         onepay.Describe(code),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, signed.Encode())
+}
+
+// parseMerchantAPI reads and authenticates a server to server call such as QueryDR or refund.
+func (s *simulator) parseMerchantAPI(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	request := flatten(r.PostForm)
+	if err := s.checkMerchant(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if request["vpc_User"] != s.config.User || request["vpc_Password"] != s.config.Password {
+		http.Error(w, "invalid user or password", http.StatusUnauthorized)
+		return nil, false
+	}
+	return request, true
+}
+
+func (s *simulator) checkMerchant(params map[string]string) error {
+	if params["vpc_Merchant"] != s.config.MerchantID || params["vpc_AccessCode"] != s.config.AccessCode {
+		return fmt.Errorf("unknown merchant %q or access code", params["vpc_Merchant"])
+	}
+	if err := onepay.Verify(params, s.config.HashCode); err != nil {
+		return fmt.Errorf("invalid vpc_SecureHash")
+	}
+	return nil
+}
+
+func (s *simulator) sign(params map[string]string) (url.Values, error) {
+	secureHash, err := onepay.Sign(params, s.config.HashCode)
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	values.Set("vpc_SecureHash", secureHash)
+	return values, nil
+}
+
+func (s *simulator) sendIPN(callbackURL string, result url.Values) {
+	resp, err := s.httpClient.Get(appendQuery(callbackURL, result))
+	if err != nil {
+		log.Printf("IPN to %s failed: %v", callbackURL, err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	log.Printf("IPN %s for %s answered %d: %s", result.Get("vpc_TxnResponseCode"), result.Get("vpc_MerchTxnRef"), resp.StatusCode, body)
+}
+
+func appendQuery(rawURL string, values url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + values.Encode()
+}
+
+func flatten(values url.Values) map[string]string {
+	params := make(map[string]string)
+	for k, v := range values {
+		params[k] = strings.Join(v, "")
+	}
+	return params
+}
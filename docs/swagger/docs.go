@@ -16,18 +16,26 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/onepay/ipn": {
+        "/admin/add-ons": {
             "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
                 "tags": [
-                    "register"
+                    "admin"
                 ],
-                "summary": "OnePay Payment Notification (IPN) Handler",
-                "operationId": "onePayIPN",
+                "summary": "List All Add-ons With Their Prices",
+                "operationId": "listAddOns",
                 "responses": {
                     "200": {
-                        "description": "responsecode=1\u0026desc=confirm-success",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AddOn"
+                            }
                         }
                     },
                     "400": {
@@ -43,15 +51,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/register": {
+            },
             "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
                 "tags": [
-                    "register"
+                    "admin"
                 ],
-                "summary": "Register a New User for the Event",
-                "operationId": "register",
+                "summary": "Create an Add-on",
+                "operationId": "createAddOn",
                 "parameters": [
                     {
                         "description": "body",
@@ -59,7 +70,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.RegistrationRequest"
+                            "$ref": "#/definitions/dto.CreateAddOnRequest"
                         }
                     }
                 ],
@@ -67,7 +78,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegistrationResponse"
+                            "$ref": "#/definitions/model.AddOn"
                         }
                     },
                     "400": {
@@ -85,21 +96,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/accompany-persons": {
-            "post": {
+        "/admin/add-ons/{addOnID}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
                 "tags": [
-                    "register"
+                    "admin"
                 ],
-                "summary": "Register Accompanying Persons for an Existing Registration",
-                "operationId": "registerAccompanyPersons",
+                "summary": "Update the Details and Capacity of an Add-on",
+                "operationId": "updateAddOn",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "addOnID",
+                        "name": "addOnID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AccompanyPersonRegistrationRequest"
+                            "$ref": "#/definitions/dto.UpdateAddOnRequest"
                         }
                     }
                 ],
@@ -107,7 +130,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegistrationResponse"
+                            "$ref": "#/definitions/model.AddOn"
                         }
                     },
                     "400": {
@@ -125,32 +148,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/file": {
-            "get": {
+        "/admin/add-ons/{addOnID}/prices": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Line items already ordered keep the price they were ordered at.",
                 "tags": [
-                    "register"
+                    "admin"
                 ],
-                "summary": "Export Registrations as XLSX",
-                "operationId": "exportRegistrationsXLSX",
+                "summary": "Replace the Prices of an Add-on",
+                "operationId": "replaceAddOnPrices",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start time (YYYY-MM-DD)",
-                        "name": "start_time",
-                        "in": "query"
+                        "description": "addOnID",
+                        "name": "addOnID",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "End time (YYYY-MM-DD)",
-                        "name": "end_time",
-                        "in": "query"
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplaceAddOnPricesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "XLSX file",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/model.AddOn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
                         }
                     },
                     "500": {
@@ -162,18 +201,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/option": {
-            "get": {
+        "/admin/check-ins": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Admits the attendee of a scanned ticket QR code. Tickets of unpaid or cancelled registrations\nand tickets reissued to someone else are refused; scanning a ticket again returns when it was first scanned.",
                 "tags": [
-                    "register"
+                    "admin"
+                ],
+                "summary": "Check an Attendee In",
+                "operationId": "checkIn",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckInRequest"
+                        }
+                    }
                 ],
-                "summary": "Get Registration Option Details",
-                "operationId": "getRegistrationOption",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.RegistrationOption"
+                            "$ref": "#/definitions/dto.CheckInResponse"
                         }
                     },
                     "400": {
@@ -182,6 +238,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -191,27 +253,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/{registerID}/registration-info": {
+        "/admin/exchange-rate": {
             "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
                 "tags": [
-                    "register"
+                    "admin"
                 ],
-                "summary": "Get Registration Information by ID",
-                "operationId": "getRegistrationInfo",
+                "summary": "Get the USD to VND Rate Applied to New Checkouts",
+                "operationId": "getExchangeRate",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExchangeRateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Override the USD to VND Rate",
+                "operationId": "setExchangeRate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "registerID",
-                        "name": "registerID",
-                        "in": "path",
-                        "required": true
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExchangeRateOverrideRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Registration"
+                            "$ref": "#/definitions/model.ExchangeRate"
                         }
                     },
                     "400": {
@@ -227,125 +328,3946 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.AccompanyPersonRegistrationRequest": {
-            "type": "object",
-            "required": [
-                "accompany_persons",
-                "email"
-            ],
-            "properties": {
-                "accompany_persons": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.AccompanyPerson"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove the USD to VND Rate Override",
+                "operationId": "clearExchangeRate",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
                     }
-                },
-                "email": {
-                    "type": "string"
                 }
             }
         },
-        "dto.RegistrationRequest": {
-            "type": "object",
-            "required": [
-                "doctorate_degree",
-                "email",
-                "registration_category",
-                "registration_option"
-            ],
-            "properties": {
-                "accompany_persons": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.AccompanyPerson"
+        "/admin/promo-codes": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List All Promo Codes and How Often They Were Used",
+                "operationId": "listPromoCodes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PromoCode"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
                     }
-                },
-                "attend_gala_dinner": {
-                    "type": "boolean"
-                },
-                "date_of_birth": {
-                    "type": "string"
-                },
-                "doctorate_degree": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
-                },
-                "institution": {
-                    "type": "string"
-                },
-                "last_name": {
-                    "type": "string"
-                },
-                "middle_name": {
-                    "type": "string"
-                },
-                "nationality": {
-                    "type": "string"
-                },
-                "phone_number": {
-                    "type": "string"
-                },
-                "registration_category": {
-                    "type": "string"
-                },
-                "registration_option": {
-                    "type": "string"
-                },
-                "sponsor": {
-                    "type": "string"
                 }
-            }
-        },
-        "dto.RegistrationResponse": {
-            "type": "object",
-            "properties": {
-                "payment_url": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a Promo Code",
+                "operationId": "createPromoCode",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/promo-codes/{promoCodeID}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Registrations already made with the code keep their discount.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update or Deactivate a Promo Code",
+                "operationId": "updatePromoCode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "promoCodeID",
+                        "name": "promoCodeID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-capacities": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the Capacities of the Registration Categories",
+                "operationId": "listCategoryCapacities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CategoryCapacity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Categories left out are unlimited. A capacity counts the paid registrations and the unpaid ones holding a seat.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replace the Capacities of the Registration Categories",
+                "operationId": "replaceCategoryCapacities",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplaceCategoryCapacitiesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CategoryCapacity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-changes": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Cancellation and Substitution Requests",
+                "operationId": "listChanges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected; every request when empty",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RegistrationChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-changes/{changeID}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "A cancellation is refunded through OnePay, or recorded to refund by hand when paid outside OnePay. A substitution hands the registration over and emails the substitute a new ticket.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Approve a Cancellation or Substitution Request",
+                "operationId": "approveChange",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changeID",
+                        "name": "changeID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecideChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-changes/{changeID}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reject a Cancellation or Substitution Request",
+                "operationId": "rejectChange",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changeID",
+                        "name": "changeID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecideChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-options": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List All Registration Options and Their Versions",
+                "operationId": "listRegistrationOptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RegistrationOption"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a Registration Option",
+                "operationId": "createRegistrationOption",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRegistrationOptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-options/{optionID}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Creates the next version of the option; the current version is deactivated but kept.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the Price of a Registration Option",
+                "operationId": "updateRegistrationOption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "optionID",
+                        "name": "optionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRegistrationOptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-options/{optionID}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Put a Registration Option on Sale",
+                "operationId": "activateRegistrationOption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "optionID",
+                        "name": "optionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-options/{optionID}/deactivate": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Take a Registration Option off Sale",
+                "operationId": "deactivateRegistrationOption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "optionID",
+                        "name": "optionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-periods": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Registration Periods",
+                "operationId": "listRegistrationPeriods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Period"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Periods must follow each other without overlaps or gaps.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replace the Registration Periods",
+                "operationId": "replaceRegistrationPeriods",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplacePeriodsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Period"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registrations/import": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "The file has the columns of the registration export. Every row is validated like a registration request and reported with its errors; the valid rows are registered as paid offline or pending with a payment URL.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import Registrations from an XLSX or CSV File",
+                "operationId": "importRegistrations",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "XLSX or CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "done or pending",
+                        "name": "payment_status",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only validate the file",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportRegistrationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registrations/{registerID}/audit-log": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the Audit Log of a Registration",
+                "operationId": "getAuditLog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AuditLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registrations/{registerID}/offline-payment/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Marks the registration paid and sends the confirmation email, as a successful OnePay payment does.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Confirm Receipt of a Bank Transfer or Cash Payment",
+                "operationId": "confirmOfflinePayment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConfirmOfflinePaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Registration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registrations/{registerID}/payment-transactions": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Payment Attempts of a Registration",
+                "operationId": "listPaymentTransactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PaymentTransaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registrations/{registerID}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Refund a Registration Payment through OnePay",
+                "operationId": "refundRegistration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Refund"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Each role with the permissions it grants on the admin routes.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the Roles Admin Users Can Have",
+                "operationId": "listRoles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RoleResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Admin Users",
+                "operationId": "listAdminUsers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AdminUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create an Admin User",
+                "operationId": "createAdminUser",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAdminUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{userID}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Changes the name, password, role or whether the user may sign in. A new password or role, or disabling the user, ends their sessions.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update an Admin User",
+                "operationId": "updateAdminUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateAdminUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{userID}/role": {
+            "put": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Ends the sessions of the user, who signs in again with the permissions of the new role.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Assign a Role to an Admin User",
+                "operationId": "assignRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the Waitlist",
+                "operationId": "listWaitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registration option category, every category when empty",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "waiting, invited, registered or expired; every entry when empty",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WaitlistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/waitlist/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Seats are offered periodically; this runs the offer at once, e.g. after raising a capacity.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Offer the Free Seats to the Waitlist Now",
+                "operationId": "inviteFromWaitlist",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WaitlistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Returns an access token to send in the session-key header of admin requests and a refresh token to renew it with.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign In as an Admin User",
+                "operationId": "login",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Ends the session of the access token, which can no longer be used or renewed.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log Out",
+                "operationId": "logout",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-everywhere": {
+            "post": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "description": "Ends every session of the signed in admin user, including this one.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log Out Everywhere",
+                "operationId": "logoutEverywhere",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the Signed In Admin User",
+                "operationId": "getCurrentUser",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AdminUser"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Returns a new access token and a new refresh token; the refresh token sent can no longer be used.\nSending a refresh token that was used already ends every session of its user.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Renew a Session",
+                "operationId": "refreshSession",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/onepay/ipn": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "OnePay Payment Notification (IPN) Handler",
+                "operationId": "onePayIPN",
+                "responses": {
+                    "200": {
+                        "description": "responsecode=1\u0026desc=confirm-success",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/onepay/result": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Read a Payment Result Token",
+                "operationId": "getPaymentResult",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token from the result page URL",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/jwt.PaymentResultClaims"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/onepay/return": {
+            "get": {
+                "description": "Verifies the paygate redirect and sends the payer to the success or failure page\nwith a signed ` + "`" + `token` + "`" + ` and the ` + "`" + `result` + "`" + ` query parameters.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "OnePay Return URL Handler",
+                "operationId": "onePayReturn",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Register a New User for the Event",
+                "operationId": "register",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/accompany-persons": {
+            "post": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Register Accompanying Persons for an Existing Registration",
+                "operationId": "registerAccompanyPersons",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AccompanyPersonRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/file": {
+            "get": {
+                "security": [
+                    {
+                        "SessionKey": []
+                    }
+                ],
+                "tags": [
+                    "register"
+                ],
+                "summary": "Export Registrations as XLSX",
+                "operationId": "exportRegistrationsXLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start time (YYYY-MM-DD)",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time (YYYY-MM-DD)",
+                        "name": "end_time",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "XLSX file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/groups": {
+            "post": {
+                "description": "Every attendee gets a registration of their own; the sponsor pays for all of them with one payment URL.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Register a Group of Attendees Paid by a Sponsor",
+                "operationId": "registerGroup",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GroupRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GroupRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/groups/{groupID}": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get a Sponsor Group and Its Registrations",
+                "operationId": "getRegistrationGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "groupID",
+                        "name": "groupID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/groups/{groupID}/payment-url": {
+            "post": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get a New Payment URL for the Unpaid Registrations of a Group",
+                "operationId": "renewGroupPaymentURL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "groupID",
+                        "name": "groupID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GroupRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/manage": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get the Registration a Manage Link Was Sent For",
+                "operationId": "getManagedRegistration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token from the manage link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Registration"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edits the name, date of birth, institution or phone number of a registration. The category,\nadd-ons, email and payment cannot be changed here; every change is recorded on the audit log.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Correct Attendee Details",
+                "operationId": "updateManagedRegistration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token from the manage link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Registration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/manage-link": {
+            "post": {
+                "description": "Sends a short-lived link to edit the registration made with the email. The answer is the same\nwhether or not the email is registered.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Email a Link to Manage a Registration",
+                "operationId": "requestManageLink",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ManageLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/option": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get Registration Option Details",
+                "operationId": "getRegistrationOption",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/options": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "List Every Registration Option on Sale",
+                "operationId": "getRegistrationCatalogue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationCatalogueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/waitlist": {
+            "post": {
+                "description": "Takes the same body as a registration. When a seat frees up the registration is made and the attendee is emailed a link to pay for it before the invitation expires.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Join the Waitlist of a Sold-out Category",
+                "operationId": "joinWaitlist",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WaitlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/waitlist/{entryID}": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get a Waitlist Entry and Its Position in the Queue",
+                "operationId": "getWaitlistEntry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entryID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WaitlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/cancellation": {
+            "post": {
+                "description": "The refund follows the cancellation policy on the day of the request. Nothing changes until an admin approves it.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Ask to Cancel a Paid Registration",
+                "operationId": "requestCancellation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancellationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/changes": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "List the Cancellation and Substitution Requests of a Registration",
+                "operationId": "listRegistrationChanges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RegistrationChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/offline-payment": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get How to Pay a Registration by Bank Transfer or Cash",
+                "operationId": "getOfflinePayment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OfflinePayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/payment-url": {
+            "post": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get a New Payment URL for an Unpaid Registration",
+                "operationId": "renewPaymentURL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/registration-info": {
+            "get": {
+                "tags": [
+                    "register"
+                ],
+                "summary": "Get Registration Information by ID",
+                "operationId": "getRegistrationInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ticket code of the QR code, for reissued tickets",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Registration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{registerID}/substitution": {
+            "post": {
+                "description": "The substitute receives a new ticket once an admin approves the request; the current QR code stops working.",
+                "tags": [
+                    "register"
+                ],
+                "summary": "Ask to Transfer a Paid Registration to Another Attendee",
+                "operationId": "requestSubstitution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "registerID",
+                        "name": "registerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubstitutionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RegistrationChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AccompanyPersonRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "accompany_persons",
+                "email"
+            ],
+            "properties": {
+                "accompany_persons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccompanyPerson"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddOnPriceRequest": {
+            "type": "object",
+            "properties": {
+                "fee_usd": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "fee_vnd": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "subtype": {
+                    "description": "Subtype is the registration period the price applies to, empty for every other period",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddOnRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.AssignRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "admin",
+                        "staff",
+                        "finance",
+                        "check_in"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jwt.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.AttendeeRequest": {
+            "type": "object",
+            "required": [
+                "doctorate_degree",
+                "email",
+                "first_name"
+            ],
+            "properties": {
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "doctorate_degree": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BankTransfer": {
+            "type": "object",
+            "properties": {
+                "account_name": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "bank_bin": {
+                    "type": "string"
+                },
+                "bank_name": {
+                    "type": "string"
+                },
+                "transfer_reference": {
+                    "description": "TransferReference is the memo the transfer must carry, it contains the registration ID",
+                    "type": "string"
+                },
+                "vietqr_image": {
+                    "type": "string"
+                },
+                "vietqr_payload": {
+                    "description": "VietQRPayload is the EMVCo payload banking apps read, VietQRImage the same as a PNG data URI",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancellationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Email of the registration, confirming the attendee asks",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CatalogueAddOn": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fee_usd": {
+                    "type": "number"
+                },
+                "fee_vnd": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "description": "units left, -1 when unlimited",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CatalogueCategory": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CatalogueOption"
+                    }
+                },
+                "remaining": {
+                    "description": "-1 when unlimited, 0 when registrants can only join the waitlist",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CatalogueOption": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "Current is true for the option sold in the current period",
+                    "type": "boolean"
+                },
+                "fee_usd": {
+                    "type": "number"
+                },
+                "fee_vnd": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "subtype": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CategoryCapacityRequest": {
+            "type": "object",
+            "required": [
+                "category"
+            ],
+            "properties": {
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "category": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheckInRequest": {
+            "type": "object",
+            "required": [
+                "registration_id"
+            ],
+            "properties": {
+                "registration_id": {
+                    "type": "string"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheckInResponse": {
+            "type": "object",
+            "properties": {
+                "accompany_persons": {
+                    "type": "integer"
+                },
+                "already_checked_in": {
+                    "description": "AlreadyCheckedIn is set when the ticket had been scanned before, at CheckedInAt",
+                    "type": "boolean"
+                },
+                "attends_gala_dinner": {
+                    "type": "boolean"
+                },
+                "checked_in_at": {
+                    "type": "string"
+                },
+                "doctorate_degree": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "registration_category": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ConfirmOfflinePaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount received, in the currency of the registration; 0 is the amount due",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAddOnRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAdminUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 10
+                },
+                "role": {
+                    "enum": [
+                        "admin",
+                        "staff",
+                        "finance",
+                        "check_in"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jwt.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CreatePromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount_usd": {
+                    "description": "for fixed",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "amount_vnd": {
+                    "description": "for fixed",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "categories": {
+                    "description": "Categories the code is accepted for, every category when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email binds the code to one registrant",
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "percent": {
+                    "description": "for percentage",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "type": {
+                    "enum": [
+                        "percentage",
+                        "fixed",
+                        "waiver"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DiscountType"
+                        }
+                    ]
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateRegistrationOptionRequest": {
+            "type": "object",
+            "required": [
+                "category"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "fee_usd": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "fee_vnd": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "subtype": {
+                    "description": "the registration period name, empty for options sold in every period",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DecideChangeRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "description": "RefundAmount replaces the amount the refund policy grants a cancellation, in its currency",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.ExchangeRateOverrideRequest": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.ExchangeRateResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "source": {
+                    "description": "Source is \"override\" when the rate was set by an admin, \"provider\" otherwise",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GroupRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "attendees",
+                "contact_email",
+                "contact_name",
+                "sponsor"
+            ],
+            "properties": {
+                "attendees": {
+                    "description": "Attendees are registered as they would register themselves; their sponsor and payment method are the group's",
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.RegistrationRequest"
+                    }
+                },
+                "contact_email": {
+                    "type": "string"
+                },
+                "contact_name": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "description": "Nationality of the sponsor, vn to pay in VND",
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "onepay_domestic",
+                        "onepay_international"
+                    ]
+                },
+                "sponsor": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GroupRegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "description": "PaymentStatus is done when there was nothing to pay and PaymentURL is empty",
+                    "type": "string"
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "registration_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ImportRegistrationsResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "row number in the file, the header being row 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "refresh_expires_at": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "RefreshToken renews the session once, until RefreshExpiresAt",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token goes into the session-key header of admin requests until ExpiresAt",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.AdminUser"
+                }
+            }
+        },
+        "dto.ManageLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OfflinePayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount due, in VND for Vietnamese attendees and USD for everyone else",
+                    "type": "integer"
+                },
+                "bank_transfer": {
+                    "description": "BankTransfer is the account to transfer to, for bank transfers only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.BankTransfer"
+                        }
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PeriodRequest": {
+            "type": "object",
+            "required": [
+                "end_at",
+                "name",
+                "start_at",
+                "time_zone"
+            ],
+            "properties": {
+                "end_at": {
+                    "type": "string",
+                    "example": "2025-11-01T00:00:00"
+                },
+                "name": {
+                    "description": "Name is the RegistrationOption subtype sold during the period, e.g. EarlyBird",
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2025-09-01T00:00:00"
+                },
+                "time_zone": {
+                    "description": "TimeZone start_at and end_at are read in, an IANA name such as Asia/Ho_Chi_Minh",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RefundRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount in the transaction currency, 0 refunds everything still refundable",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "payment_transaction_id": {
+                    "description": "PaymentTransactionID defaults to the registration payment when empty",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegistrationCatalogueResponse": {
+            "type": "object",
+            "properties": {
+                "add_ons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CatalogueAddOn"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CatalogueCategory"
+                    }
+                },
+                "current_period": {
+                    "$ref": "#/definitions/model.Period"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Period"
+                    }
+                },
+                "registration_open": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RegistrationRequest": {
+            "type": "object",
+            "required": [
+                "doctorate_degree",
+                "email",
+                "registration_category",
+                "registration_option"
+            ],
+            "properties": {
+                "accompany_persons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccompanyPerson"
+                    }
+                },
+                "add_ons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AddOnRequest"
+                    }
+                },
+                "attend_gala_dinner": {
+                    "type": "boolean"
+                },
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "doctorate_degree": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "description": "PaymentMethod defaults to domestic cards for Vietnamese attendees and international cards otherwise.\nbank_transfer and cash are paid outside OnePay, bank_transfer in VND only.",
+                    "type": "string",
+                    "enum": [
+                        "onepay_domestic",
+                        "onepay_international",
+                        "bank_transfer",
+                        "cash"
+                    ]
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "registration_category": {
+                    "type": "string"
+                },
+                "registration_option": {
+                    "type": "string"
+                },
+                "sponsor": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "bank_transfer": {
+                    "description": "BankTransfer is a VietQR transfer paying the registration, for Vietnamese attendees: the way to pay\nwith the bank_transfer method and an alternative to PaymentURL with the OnePay ones",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.BankTransfer"
+                        }
+                    ]
+                },
+                "payment_status": {
+                    "description": "PaymentStatus is done when there was nothing to pay, e.g. the fee was waived, and PaymentURL is empty",
+                    "type": "string"
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplaceAddOnPricesRequest": {
+            "type": "object",
+            "required": [
+                "prices"
+            ],
+            "properties": {
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AddOnPriceRequest"
+                    }
+                }
+            }
+        },
+        "dto.ReplaceCategoryCapacitiesRequest": {
+            "type": "object",
+            "required": [
+                "capacities"
+            ],
+            "properties": {
+                "capacities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryCapacityRequest"
+                    }
+                }
+            }
+        },
+        "dto.ReplacePeriodsRequest": {
+            "type": "object",
+            "required": [
+                "periods"
+            ],
+            "properties": {
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PeriodRequest"
+                    }
+                }
+            }
+        },
+        "dto.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwt.Permission"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/jwt.Role"
+                }
+            }
+        },
+        "dto.SubstitutionRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "substitute"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Email of the registration, confirming the attendee asks",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "substitute": {
+                    "$ref": "#/definitions/dto.AttendeeRequest"
+                }
+            }
+        },
+        "dto.UpdateAddOnRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateAdminUserRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 10
+                },
+                "role": {
+                    "enum": [
+                        "admin",
+                        "staff",
+                        "finance",
+                        "check_in"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jwt.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.UpdateDetailsRequest": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "doctorate_degree": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "institution": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "middle_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "phone_number": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "dto.UpdatePromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount_usd": {
+                    "description": "for fixed",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "amount_vnd": {
+                    "description": "for fixed",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "categories": {
+                    "description": "Categories the code is accepted for, every category when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email binds the code to one registrant",
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "percent": {
+                    "description": "for percentage",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "type": {
+                    "enum": [
+                        "percentage",
+                        "fixed",
+                        "waiver"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DiscountType"
+                        }
+                    ]
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateRegistrationOptionRequest": {
+            "type": "object",
+            "properties": {
+                "fee_usd": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "fee_vnd": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "errors.AppError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwt.PaymentResultClaims": {
+            "type": "object",
+            "properties": {
+                "aud": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "iat": {
+                    "type": "integer"
+                },
+                "iss": {
+                    "type": "string"
+                },
+                "jti": {
+                    "type": "string"
+                },
+                "merch_txn_ref": {
+                    "type": "string"
+                },
+                "nbf": {
+                    "type": "integer"
+                },
+                "order_info": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwt.Permission": {
+            "type": "string",
+            "enum": [
+                "registrations:read",
+                "registrations:edit",
+                "registrations:export",
+                "registrations:check_in",
+                "payments:read",
+                "payments:manage",
+                "settings:read",
+                "settings:manage",
+                "users:manage"
+            ],
+            "x-enum-varnames": [
+                "PermissionRegistrationsRead",
+                "PermissionRegistrationsEdit",
+                "PermissionRegistrationsExport",
+                "PermissionRegistrationsCheckIn",
+                "PermissionPaymentsRead",
+                "PermissionPaymentsManage",
+                "PermissionSettingsRead",
+                "PermissionSettingsManage",
+                "PermissionUsersManage"
+            ]
+        },
+        "jwt.Role": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "staff",
+                "finance",
+                "check_in",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "AdminRole",
+                "StaffRole",
+                "FinanceRole",
+                "CheckInRole",
+                "UserRole"
+            ]
+        },
+        "model.AccompanyPerson": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddOn": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AddOnPrice"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddOnPrice": {
+            "type": "object",
+            "properties": {
+                "add_on_id": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fee_usd": {
+                    "type": "number"
+                },
+                "fee_vnd": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "subtype": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AdminUser": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "stored lower-case",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Attendee": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "doctorate_degree": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditAction": {
+            "type": "string",
+            "enum": [
+                "cancellation_requested",
+                "substitution_requested",
+                "change_approved",
+                "change_rejected",
+                "cancelled",
+                "substituted",
+                "details_edited",
+                "checked_in"
+            ],
+            "x-enum-varnames": [
+                "AuditActionCancellationRequested",
+                "AuditActionSubstitutionRequested",
+                "AuditActionChangeApproved",
+                "AuditActionChangeRejected",
+                "AuditActionCancelled",
+                "AuditActionSubstituted",
+                "AuditActionDetailsEdited",
+                "AuditActionCheckedIn"
+            ]
+        },
+        "model.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "actor": {
+                    "description": "Actor is the admin user ID, or the attendee's email for their own requests",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CategoryCapacity": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "description": "Reserved is the number of seats paid or held, Waiting the number of attendees on the waitlist",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "waiting": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChangeStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ChangeStatusPending",
+                "ChangeStatusApproved",
+                "ChangeStatusRejected"
+            ]
+        },
+        "model.ChangeType": {
+            "type": "string",
+            "enum": [
+                "cancellation",
+                "substitution"
+            ],
+            "x-enum-varnames": [
+                "ChangeTypeCancellation",
+                "ChangeTypeSubstitution"
+            ]
+        },
+        "model.DiscountType": {
+            "type": "string",
+            "enum": [
+                "percentage",
+                "fixed",
+                "waiver"
+            ],
+            "x-enum-varnames": [
+                "DiscountTypePercentage",
+                "DiscountTypeFixed",
+                "DiscountTypeWaiver"
+            ]
+        },
+        "model.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PaymentTransaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "e.g., 1800000 = 1,800,000 VND, not multiplied by 100",
+                    "type": "integer"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "exchange_rate": {
+                    "description": "USD to VND rate Amount was converted with, 0 if not converted",
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "description": "set instead of RegistrationID for a sponsor group payment",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merch_txn_ref": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "order_info": {
+                    "type": "string"
+                },
+                "order_type": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "raw_query": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "errors.AppError": {
+        "model.Period": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "the time zone the period was entered in",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PromoCode": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount_usd": {
+                    "description": "for DiscountTypeFixed",
+                    "type": "number"
+                },
+                "amount_vnd": {
+                    "description": "for DiscountTypeFixed",
+                    "type": "integer"
+                },
+                "categories": {
+                    "description": "Categories the code is accepted for, every category when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "code": {
+                    "description": "stored upper-case",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email binds the code to one registrant, anyone may use it when empty",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "0 is unlimited",
                     "type": "integer"
                 },
-                "error": {
-                    "type": "boolean"
+                "percent": {
+                    "description": "for DiscountTypePercentage, e.g. 20 = 20%",
+                    "type": "number"
                 },
-                "message": {
+                "type": {
+                    "$ref": "#/definitions/model.DiscountType"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "used": {
+                    "description": "Used is the number of paid registrations made with the code",
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
                     "type": "string"
                 }
             }
         },
-        "model.AccompanyPerson": {
+        "model.Refund": {
             "type": "object",
             "properties": {
-                "date_of_birth": {
+                "amount": {
+                    "description": "same unit as PaymentTransaction.Amount",
+                    "type": "integer"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "first_name": {
+                "currency": {
                     "type": "string"
                 },
-                "last_name": {
+                "id": {
                     "type": "string"
                 },
-                "middle_name": {
+                "merch_txn_ref": {
                     "type": "string"
                 },
-                "payment_status": {
+                "message": {
+                    "type": "string"
+                },
+                "org_merch_txn_ref": {
+                    "type": "string"
+                },
+                "payment_transaction_id": {
+                    "type": "string"
+                },
+                "raw_query": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
@@ -364,12 +4286,26 @@ const docTemplate = `{
                         "$ref": "#/definitions/model.AccompanyPerson"
                     }
                 },
+                "checked_in_at": {
+                    "description": "CheckedInAt is when the attendee's ticket was scanned at the venue, CheckedInBy the admin user who scanned it",
+                    "type": "string"
+                },
+                "checked_in_by": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
                 "date_of_birth": {
                     "type": "string"
                 },
+                "discount_usd": {
+                    "description": "taken off the registration option fee by the promo code",
+                    "type": "number"
+                },
+                "discount_vnd": {
+                    "type": "integer"
+                },
                 "doctorate_degree": {
                     "type": "string"
                 },
@@ -379,6 +4315,10 @@ const docTemplate = `{
                 "first_name": {
                     "type": "string"
                 },
+                "group_id": {
+                    "description": "GroupID is the sponsor group that registered and pays for the registration",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -388,18 +4328,33 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
+                "line_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RegistrationLineItem"
+                    }
+                },
                 "middle_name": {
                     "type": "string"
                 },
                 "nationality": {
                     "type": "string"
                 },
+                "payment_method": {
+                    "type": "string"
+                },
                 "payment_status": {
                     "type": "string"
                 },
                 "phone_number": {
                     "type": "string"
                 },
+                "promo_code": {
+                    "type": "string"
+                },
+                "promo_code_id": {
+                    "type": "string"
+                },
                 "registrationOption": {
                     "$ref": "#/definitions/model.RegistrationOption"
                 },
@@ -409,6 +4364,114 @@ const docTemplate = `{
                 "registration_option_id": {
                     "type": "string"
                 },
+                "reserved_until": {
+                    "description": "ReservedUntil is when an unpaid registration stops holding its seat, see CategoryCapacity",
+                    "type": "string"
+                },
+                "sponsor": {
+                    "type": "string"
+                },
+                "transfer_reference": {
+                    "description": "TransferReference is the memo a bank transfer for the registration must carry",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RegistrationChange": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "type": "integer"
+                },
+                "refund_id": {
+                    "description": "the OnePay refund made on approval",
+                    "type": "string"
+                },
+                "refund_percent": {
+                    "description": "RefundPercent and RefundAmount are what the refund policy grants on the day of the request,\nRefundAmount in Currency; the admin may refund another amount when approving",
+                    "type": "number"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ChangeStatus"
+                },
+                "substitute": {
+                    "description": "Substitute is who takes the registration over, for substitutions only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Attendee"
+                        }
+                    ]
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ChangeType"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RegistrationGroup": {
+            "type": "object",
+            "properties": {
+                "contact_email": {
+                    "type": "string"
+                },
+                "contact_name": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "description": "Nationality of the sponsor decides the currency it pays in, as for a registrant",
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "registrations": {
+                    "description": "no foreign key constraint, group_id is empty for the registrations outside a group",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Registration"
+                    }
+                },
                 "sponsor": {
                     "type": "string"
                 },
@@ -417,6 +4480,52 @@ const docTemplate = `{
                 }
             }
         },
+        "model.RegistrationLineItem": {
+            "type": "object",
+            "properties": {
+                "accompany_persons": {
+                    "description": "AccompanyPersons is true when the item was bought for the accompanying persons",
+                    "type": "boolean"
+                },
+                "add_on_id": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "TransactionID is the accompany person purchase the item was ordered with, empty for the registration itself",
+                    "type": "string"
+                },
+                "unit_fee_usd": {
+                    "type": "number"
+                },
+                "unit_fee_vnd": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "model.RegistrationOption": {
             "type": "object",
             "properties": {
@@ -445,10 +4554,71 @@ const docTemplate = `{
                     "description": "e.g., \"Early-bird\"",
                     "type": "string"
                 },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version counts the price changes of a category and subtype. A price change creates a new\nversion so registrations keep referencing the option they paid for.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WaitlistEntry": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_at": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Position is the place in the queue of a waiting entry, 1 being the next invited",
+                    "type": "integer"
+                },
+                "registration_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.WaitlistStatus"
+                },
                 "updatedAt": {
                     "type": "string"
                 }
             }
+        },
+        "model.WaitlistStatus": {
+            "type": "string",
+            "enum": [
+                "waiting",
+                "invited",
+                "registered",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "WaitlistStatusWaiting",
+                "WaitlistStatusInvited",
+                "WaitlistStatusRegistered",
+                "WaitlistStatusExpired"
+            ]
         }
     },
     "securityDefinitions": {
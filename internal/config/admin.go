@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Admin configures the sessions of admin users and the first admin created on an empty user store.
+type Admin struct {
+	// AccessTokenTTL is how long the session-key tokens last, RefreshTokenTTL how long a session
+	// can be renewed without signing in again
+	AccessTokenTTL  string `env:"ACCESS_TOKEN_TTL" envDefault:"15m" json:"accessTokenTTL"`
+	RefreshTokenTTL string `env:"REFRESH_TOKEN_TTL" envDefault:"168h" json:"refreshTokenTTL"`
+	// BootstrapEmail and BootstrapPassword create an admin when there are no admin users yet
+	BootstrapEmail    string `env:"BOOTSTRAP_EMAIL" json:"bootstrapEmail"`
+	BootstrapPassword string `env:"BOOTSTRAP_PASSWORD" json:"-"`
+}
+
+func (a Admin) GetAccessTokenTTL() time.Duration {
+	duration, err := time.ParseDuration(a.AccessTokenTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse admin access token ttl"))
+	}
+	return duration
+}
+
+func (a Admin) GetRefreshTokenTTL() time.Duration {
+	duration, err := time.ParseDuration(a.RefreshTokenTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse admin refresh token ttl"))
+	}
+	return duration
+}
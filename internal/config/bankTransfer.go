@@ -0,0 +1,17 @@
+package config
+
+// BankTransfer is the account Vietnamese attendees may pay into instead of going through OnePay.
+type BankTransfer struct {
+	// BankBIN is the NAPAS bank identification number used in VietQR payloads, e.g. 970436 for Vietcombank
+	BankBIN       string `env:"BANK_BIN" json:"bankBIN"`
+	BankName      string `env:"BANK_NAME" json:"bankName"`
+	AccountNumber string `env:"ACCOUNT_NUMBER" json:"accountNumber"`
+	AccountName   string `env:"ACCOUNT_NAME" json:"accountName"`
+	// ReferencePrefix starts every transfer reference, so the accountant can spot them on the statement
+	ReferencePrefix string `env:"REFERENCE_PREFIX" envDefault:"ASHNO" json:"referencePrefix"`
+}
+
+// Enabled reports whether an account is configured to take bank transfers.
+func (b BankTransfer) Enabled() bool {
+	return b.BankBIN != "" && b.AccountNumber != ""
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Cancellation struct {
+	// RefundPolicy is the share of the payment refunded to cancellations requested up to a date,
+	// e.g. "2025-09-01:100,2025-10-01:50". Cancellations requested later are not refunded.
+	RefundPolicy string `env:"REFUND_POLICY" json:"refundPolicy"`
+}
+
+// RefundTier refunds Percent of the payment to cancellations requested before Until.
+type RefundTier struct {
+	Until   time.Time
+	Percent float64
+}
+
+// GetRefundPolicy returns the refund tiers sorted by date. Each date is inclusive, in UTC.
+func (c Cancellation) GetRefundPolicy() []RefundTier {
+	var tiers []RefundTier
+	for _, entry := range strings.Split(c.RefundPolicy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		date, percent, ok := strings.Cut(entry, ":")
+		if !ok {
+			panic(errors.Errorf("Failed to parse cancellation refund policy entry %q", entry))
+		}
+		day, err := time.Parse(time.DateOnly, strings.TrimSpace(date))
+		if err != nil {
+			panic(errors.Wrap(err, "Failed to parse cancellation refund policy date"))
+		}
+		share, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || share < 0 || share > 100 {
+			panic(errors.Errorf("Failed to parse cancellation refund policy percent %q", percent))
+		}
+		tiers = append(tiers, RefundTier{Until: day.AddDate(0, 0, 1), Percent: share})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Until.Before(tiers[j].Until) })
+	return tiers
+}
+
+// GetRefundPercent is the share of the payment refunded to a cancellation requested at t.
+func (c Cancellation) GetRefundPercent(t time.Time) float64 {
+	for _, tier := range c.GetRefundPolicy() {
+		if t.Before(tier.Until) {
+			return tier.Percent
+		}
+	}
+	return 0
+}
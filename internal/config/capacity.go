@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Capacity struct {
+	OfflineHold      string `env:"OFFLINE_HOLD" envDefault:"72h" json:"offlineHold"`
+	InvitationTTL    string `env:"INVITATION_TTL" envDefault:"48h" json:"invitationTTL"`
+	WaitlistInterval string `env:"WAITLIST_INTERVAL" envDefault:"5m" json:"waitlistInterval"`
+}
+
+// GetOfflineHold is how long a registration paid by bank transfer or cash holds its seat
+// while an admin has not confirmed the payment.
+func (c Capacity) GetOfflineHold() time.Duration {
+	duration, err := time.ParseDuration(c.OfflineHold)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse capacity offline hold"))
+	}
+	return duration
+}
+
+// GetInvitationTTL is how long an attendee invited from the waitlist has to pay for the seat.
+func (c Capacity) GetInvitationTTL() time.Duration {
+	duration, err := time.ParseDuration(c.InvitationTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse capacity invitation ttl"))
+	}
+	return duration
+}
+
+// GetWaitlistInterval is how often freed seats are offered to the waitlist.
+func (c Capacity) GetWaitlistInterval() time.Duration {
+	duration, err := time.ParseDuration(c.WaitlistInterval)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse capacity waitlist interval"))
+	}
+	return duration
+}
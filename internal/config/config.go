@@ -6,13 +6,22 @@ import (
 )
 
 type Config struct {
-	Database Database `envPrefix:"DATABASE_"`
-	Server   Server   `envPrefix:"SERVER_"`
-	Log      Log      `envPrefix:"LOG_"`
-	Swagger  Swagger  `envPrefix:"SWAGGER_"`
-	OnePay   OnePay   `envPrefix:"ONE_PAY_VND_"`
-	SendGrip SendGrip `envPrefix:"SEND_GRIP_"`
-	Event    Event    `envPrefix:"EVENT_"`
+	Database            Database     `envPrefix:"DATABASE_"`
+	Server              Server       `envPrefix:"SERVER_"`
+	Log                 Log          `envPrefix:"LOG_"`
+	Swagger             Swagger      `envPrefix:"SWAGGER_"`
+	OnePay              OnePay       `envPrefix:"ONE_PAY_VND_"`
+	OnePayInternational OnePay       `envPrefix:"ONE_PAY_USD_"`
+	SendGrip            SendGrip     `envPrefix:"SEND_GRIP_"`
+	Event               Event        `envPrefix:"EVENT_"`
+	Reconcile           Reconcile    `envPrefix:"RECONCILE_"`
+	Rate                Rate         `envPrefix:"RATE_"`
+	Payment             Payment      `envPrefix:"PAYMENT_"`
+	BankTransfer        BankTransfer `envPrefix:"BANK_TRANSFER_"`
+	Cancellation        Cancellation `envPrefix:"CANCELLATION_"`
+	Capacity            Capacity     `envPrefix:"CAPACITY_"`
+	Manage              Manage       `envPrefix:"MANAGE_"`
+	Admin               Admin        `envPrefix:"ADMIN_"`
 }
 
 var config Config
@@ -90,6 +90,22 @@ func InitDatabase() {
 		model.Registration{},
 		model.RegistrationOption{},
 		model.AccompanyPersonDB{},
+		model.PaymentTransaction{},
+		model.Refund{},
+		model.ExchangeRate{},
+		model.Period{},
+		model.AddOn{},
+		model.AddOnPrice{},
+		model.RegistrationLineItem{},
+		model.PromoCode{},
+		model.RegistrationGroup{},
+		model.RegistrationChange{},
+		model.AuditLog{},
+		model.CategoryCapacity{},
+		model.WaitlistEntry{},
+		model.AdminUser{},
+		model.RefreshToken{},
+		model.RevokedToken{},
 	)
 	if err != nil {
 		panic(errs.Wrap(err, "Failed to migrate database"))
@@ -98,19 +114,105 @@ func InitDatabase() {
 		log.Fatalf("failed to seed registration options: %v", err)
 	}
 
+	if err := seedRegistrationPeriods(db); err != nil {
+		log.Fatalf("failed to seed registration periods: %v", err)
+	}
+
+	if err := migrateGalaDinnerAddOn(db); err != nil {
+		log.Fatalf("failed to migrate gala dinner add-on: %v", err)
+	}
+
 	DB = db
 }
 
+// seedRegistrationPeriods creates the 2025 schedule when no period has been set up yet.
+func seedRegistrationPeriods(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&model.Period{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	periods := []model.Period{
+		{Name: string(model.EarlyBird), StartAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), TimeZone: "UTC"},
+		{Name: string(model.Regular), StartAt: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), TimeZone: "UTC"},
+		{Name: string(model.OnSite), StartAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC), TimeZone: "UTC"},
+	}
+	return db.Create(&periods).Error
+}
+
+// migrateGalaDinnerAddOn creates the gala dinner add-on, takes the "+ Gala Dinner" options off sale
+// and records the gala dinners already bought for accompanying persons as line items.
+func migrateGalaDinnerAddOn(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var addOn model.AddOn
+		err := tx.Where("code = ?", model.AddOnCodeGalaDinner).First(&addOn).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			addOn = model.AddOn{
+				Code:   model.AddOnCodeGalaDinner,
+				Name:   "Gala Dinner",
+				Active: true,
+				Prices: []model.AddOnPrice{{Subtype: "", FeeUSD: 100, FeeVND: 1000000}},
+			}
+			if err := tx.Create(&addOn).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.RegistrationOption{}).
+			Where("category IN ?", []string{string(model.DoctorAndDinnerCategory), string(model.StudentAndDinnerCategory)}).
+			Update("active", false).Error
+		if err != nil {
+			return err
+		}
+
+		var registrations []model.Registration
+		err = tx.Where("jsonb_array_length(COALESCE(accompany_persons, '[]'::jsonb)) > 0").
+			Where("NOT EXISTS (SELECT 1 FROM registration_line_items WHERE registration_line_items.registration_id = registrations.id)").
+			Find(&registrations).Error
+		if err != nil {
+			return err
+		}
+		var items []model.RegistrationLineItem
+		for _, reg := range registrations {
+			quantities := map[string]int{}
+			for _, person := range reg.AccompanyPersons {
+				status := person.PaymentStatus
+				if status == "" {
+					status = string(model.LineItemStatusPending)
+				}
+				quantities[status]++
+			}
+			for status, quantity := range quantities {
+				items = append(items, model.RegistrationLineItem{
+					RegistrationID:   reg.Id,
+					AddOnID:          addOn.Id,
+					Code:             addOn.Code,
+					Name:             addOn.Name,
+					AccompanyPersons: true,
+					Quantity:         quantity,
+					UnitFeeUSD:       100,
+					UnitFeeVND:       1000000,
+					Status:           status,
+				})
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+}
+
 func seedRegistrationOptions(db *gorm.DB) error {
 	options := []model.RegistrationOption{
 		{Category: string(model.DoctorCategory), Subtype: string(model.EarlyBird), FeeUSD: 500, FeeVND: 1800000, Active: true},
 		{Category: string(model.DoctorCategory), Subtype: string(model.Regular), FeeUSD: 600, FeeVND: 2200000, Active: true},
 		{Category: string(model.DoctorCategory), Subtype: string(model.OnSite), FeeUSD: 700, FeeVND: 3000000, Active: true},
 		{Category: string(model.StudentCategory), Subtype: "", FeeUSD: 300, FeeVND: 1500000, Active: true},
-		{Category: string(model.DoctorAndDinnerCategory), Subtype: string(model.EarlyBird), FeeUSD: 600, FeeVND: 2800000, Active: true},
-		{Category: string(model.DoctorAndDinnerCategory), Subtype: string(model.Regular), FeeUSD: 700, FeeVND: 3200000, Active: true},
-		{Category: string(model.DoctorAndDinnerCategory), Subtype: string(model.OnSite), FeeUSD: 800, FeeVND: 4000000, Active: true},
-		{Category: string(model.StudentAndDinnerCategory), Subtype: "", FeeUSD: 400, FeeVND: 2500000, Active: true},
 	}
 
 	for _, opt := range options {
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Manage configures the magic links attendees edit their registration with.
+type Manage struct {
+	LinkTTL string `env:"LINK_TTL" envDefault:"30m" json:"linkTTL"`
+	// PageURL is the page the link opens with ?token=, the manage page under OnePay.ReturnURL when empty
+	PageURL string `env:"PAGE_URL" json:"pageURL"`
+}
+
+// GetLinkTTL is how long a magic link can be used for.
+func (m Manage) GetLinkTTL() time.Duration {
+	duration, err := time.ParseDuration(m.LinkTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse manage link ttl"))
+	}
+	return duration
+}
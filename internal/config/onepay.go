@@ -6,4 +6,17 @@ type OnePay struct {
 	AccessCode string `env:"ACCESS_CODE" json:"access-code"`
 	HashCode   string `env:"HASHCODE" json:"hash_code"`
 	ReturnURL  string `env:"RETURN_URL" json:"return_url"`
+	Currency   string `env:"CURRENCY" envDefault:"VND" json:"currency"`
+	// SendTimeout passes the link expiry to the paygate as vpc_TimeOut, for merchants it is enabled for
+	SendTimeout bool `env:"SEND_TIMEOUT" envDefault:"false" json:"send_timeout"`
+
+	QueryDREndpoint string `env:"QUERY_DR_ENDPOINT" json:"query_dr_endpoint"`
+	RefundEndpoint  string `env:"REFUND_ENDPOINT" json:"refund_endpoint"`
+	User            string `env:"USER" json:"user"`
+	Password        string `env:"PASSWORD" json:"password"`
+}
+
+// IsConfigured reports whether payments can be signed and sent to the merchant.
+func (o OnePay) IsConfigured() bool {
+	return o.Endpoint != "" && o.MerchantID != "" && o.AccessCode != "" && o.HashCode != ""
 }
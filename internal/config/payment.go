@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Payment struct {
+	LinkTTL     string `env:"LINK_TTL" envDefault:"30m" json:"linkTTL"`
+	ExpiryGrace string `env:"EXPIRY_GRACE" envDefault:"5m" json:"expiryGrace"`
+	// SuccessURL and FailureURL are the pages the payer is sent to after the paygate,
+	// the registration page under OnePay.ReturnURL when empty
+	SuccessURL     string `env:"SUCCESS_URL" json:"successURL"`
+	FailureURL     string `env:"FAILURE_URL" json:"failureURL"`
+	ResultTokenTTL string `env:"RESULT_TOKEN_TTL" envDefault:"1h" json:"resultTokenTTL"`
+}
+
+// GetLinkTTL is how long a generated payment URL can be paid with.
+func (p Payment) GetLinkTTL() time.Duration {
+	duration, err := time.ParseDuration(p.LinkTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse payment link ttl"))
+	}
+	return duration
+}
+
+// GetExpiryGrace is how late after its expiry a payment result is still accepted,
+// since a payer may submit the paygate form right before the link expires.
+func (p Payment) GetExpiryGrace() time.Duration {
+	duration, err := time.ParseDuration(p.ExpiryGrace)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse payment expiry grace"))
+	}
+	return duration
+}
+
+func (p Payment) GetResultTokenTTL() time.Duration {
+	duration, err := time.ParseDuration(p.ResultTokenTTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse payment result token ttl"))
+	}
+	return duration
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Rate struct {
+	// Provider is either "http" or "fixed"
+	Provider string  `env:"PROVIDER" envDefault:"http" json:"provider"`
+	Endpoint string  `env:"ENDPOINT" envDefault:"https://open.er-api.com/v6/latest/USD" json:"endpoint"`
+	Fixed    float64 `env:"FIXED" envDefault:"26000" json:"fixed"`
+	TTL      string  `env:"TTL" envDefault:"1h" json:"ttl"`
+	Timeout  string  `env:"TIMEOUT" envDefault:"10s" json:"timeout"`
+}
+
+// GetTTL is how long a fetched rate is used before the provider is asked again.
+func (r Rate) GetTTL() time.Duration {
+	duration, err := time.ParseDuration(r.TTL)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse rate ttl"))
+	}
+	return duration
+}
+
+func (r Rate) GetTimeout() time.Duration {
+	duration, err := time.ParseDuration(r.Timeout)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse rate timeout"))
+	}
+	return duration
+}
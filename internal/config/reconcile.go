@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Reconcile struct {
+	Enabled      bool   `env:"ENABLED" envDefault:"true" json:"enabled"`
+	Interval     string `env:"INTERVAL" envDefault:"5m" json:"interval"`
+	PendingAfter string `env:"PENDING_AFTER" envDefault:"15m" json:"pendingAfter"`
+	AbandonAfter string `env:"ABANDON_AFTER" envDefault:"24h" json:"abandonAfter"`
+	BatchSize    int    `env:"BATCH_SIZE" envDefault:"100" json:"batchSize"`
+}
+
+func (r Reconcile) GetInterval() time.Duration {
+	duration, err := time.ParseDuration(r.Interval)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse reconcile interval"))
+	}
+	return duration
+}
+
+// GetPendingAfter is how long an attempt may wait for its IPN before OnePay is asked about it.
+func (r Reconcile) GetPendingAfter() time.Duration {
+	duration, err := time.ParseDuration(r.PendingAfter)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse reconcile pending after"))
+	}
+	return duration
+}
+
+// GetAbandonAfter is how long an attempt OnePay has never seen is kept pending before it is abandoned.
+func (r Reconcile) GetAbandonAfter() time.Duration {
+	duration, err := time.ParseDuration(r.AbandonAfter)
+	if err != nil {
+		panic(errors.Wrap(err, "Failed to parse reconcile abandon after"))
+	}
+	return duration
+}
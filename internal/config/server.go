@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/pkg/errors"
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,16 @@ type Server struct {
 	ReadTimeout string `env:"READ_TIMEOUT" json:"readTimeout"`
 	JwtKey      string `env:"JWT_KEY" json:"jwtKey"`
 	EncryptKey  string `env:"ENCRYPT_KEY" json:"encryptKey"`
+	// PublicURL is where OnePay reaches this service, e.g. https://api.ashno.vn
+	PublicURL string `env:"PUBLIC_URL" json:"publicURL"`
+}
+
+// GetPublicURL is the base of the callback URLs given to OnePay.
+func (s Server) GetPublicURL() string {
+	if s.PublicURL != "" {
+		return strings.TrimSuffix(s.PublicURL, "/")
+	}
+	return s.Host
 }
 
 func (s Server) GetAddr() string {
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddOnController struct {
+	addOnSvc service.AddOnService
+}
+
+// @Summary List All Add-ons With Their Prices
+// @Id listAddOns
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.AddOn
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/add-ons [get]
+func (u *AddOnController) HandleListAddOns(ctx *gin.Context) {
+	addOns, err := u.addOnSvc.ListAddOns()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, addOns)
+}
+
+// @Summary Create an Add-on
+// @Id createAddOn
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.CreateAddOnRequest true "body"
+// @Success 200 {object} model.AddOn
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/add-ons [post]
+func (u *AddOnController) HandleCreateAddOn(ctx *gin.Context) {
+	var req dto.CreateAddOnRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	addOn, err := u.addOnSvc.CreateAddOn(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, addOn)
+}
+
+// @Summary Update the Details and Capacity of an Add-on
+// @Id updateAddOn
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param addOnID path string true "addOnID"
+// @Param body body dto.UpdateAddOnRequest true "body"
+// @Success 200 {object} model.AddOn
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/add-ons/{addOnID} [put]
+func (u *AddOnController) HandleUpdateAddOn(ctx *gin.Context) {
+	var req dto.UpdateAddOnRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	addOn, err := u.addOnSvc.UpdateAddOn(ctx.Param("addOnID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, addOn)
+}
+
+// @Summary Replace the Prices of an Add-on
+// @Description Line items already ordered keep the price they were ordered at.
+// @Id replaceAddOnPrices
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param addOnID path string true "addOnID"
+// @Param body body dto.ReplaceAddOnPricesRequest true "body"
+// @Success 200 {object} model.AddOn
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/add-ons/{addOnID}/prices [put]
+func (u *AddOnController) HandleReplacePrices(ctx *gin.Context) {
+	var req dto.ReplaceAddOnPricesRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	addOn, err := u.addOnSvc.ReplacePrices(ctx.Param("addOnID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, addOn)
+}
+
+func NewAddOnController(addOnSvc service.AddOnService) *AddOnController {
+	return &AddOnController{
+		addOnSvc: addOnSvc,
+	}
+}
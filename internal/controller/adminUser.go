@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/middleware"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminUserController signs admin users in and out and lets admins manage them.
+type AdminUserController struct {
+	adminUserSvc service.AdminUserService
+}
+
+// @Summary Sign In as an Admin User
+// @Description Returns an access token to send in the session-key header of admin requests and a refresh token to renew it with.
+// @Id login
+// @Tags auth
+// @version 1.0
+// @Param body body dto.LoginRequest true "body"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /auth/login [post]
+func (u *AdminUserController) HandleLogin(ctx *gin.Context) {
+	var req dto.LoginRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	session, err := u.adminUserSvc.Login(req.Email, req.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, session)
+}
+
+// @Summary Renew a Session
+// @Description Returns a new access token and a new refresh token; the refresh token sent can no longer be used.
+// @Description Sending a refresh token that was used already ends every session of its user.
+// @Id refreshSession
+// @Tags auth
+// @version 1.0
+// @Param body body dto.RefreshRequest true "body"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /auth/refresh [post]
+func (u *AdminUserController) HandleRefresh(ctx *gin.Context) {
+	var req dto.RefreshRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	session, err := u.adminUserSvc.Refresh(req.RefreshToken)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, session)
+}
+
+// @Summary Log Out
+// @Description Ends the session of the access token, which can no longer be used or renewed.
+// @Id logout
+// @Tags auth
+// @version 1.0
+// @Security SessionKey
+// @Success 204
+// @Failure 401 {object} errors.AppError
+// @Router /auth/logout [post]
+func (u *AdminUserController) HandleLogout(ctx *gin.Context) {
+	claims, ok := middleware.GetCurrentUserClaims(ctx)
+	if !ok {
+		handleError(ctx, errors.ErrInvalidSession)
+		return
+	}
+	if err := u.adminUserSvc.Logout(claims); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Log Out Everywhere
+// @Description Ends every session of the signed in admin user, including this one.
+// @Id logoutEverywhere
+// @Tags auth
+// @version 1.0
+// @Security SessionKey
+// @Success 204
+// @Failure 401 {object} errors.AppError
+// @Router /auth/logout-everywhere [post]
+func (u *AdminUserController) HandleLogoutEverywhere(ctx *gin.Context) {
+	if err := u.adminUserSvc.LogoutEverywhere(currentUserID(ctx)); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Get the Signed In Admin User
+// @Id getCurrentUser
+// @Tags auth
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {object} model.AdminUser
+// @Failure 401 {object} errors.AppError
+// @Router /auth/me [get]
+func (u *AdminUserController) HandleGetCurrentUser(ctx *gin.Context) {
+	user, err := u.adminUserSvc.GetUser(currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary List Admin Users
+// @Id listAdminUsers
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.AdminUser
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/users [get]
+func (u *AdminUserController) HandleListUsers(ctx *gin.Context) {
+	users, err := u.adminUserSvc.ListUsers()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, users)
+}
+
+// @Summary Create an Admin User
+// @Id createAdminUser
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.CreateAdminUserRequest true "body"
+// @Success 200 {object} model.AdminUser
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/users [post]
+func (u *AdminUserController) HandleCreateUser(ctx *gin.Context) {
+	var req dto.CreateAdminUserRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	user, err := u.adminUserSvc.CreateUser(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary Update an Admin User
+// @Description Changes the name, password, role or whether the user may sign in. A new password or role, or disabling the user, ends their sessions.
+// @Id updateAdminUser
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param userID path string true "userID"
+// @Param body body dto.UpdateAdminUserRequest true "body"
+// @Success 200 {object} model.AdminUser
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/users/{userID} [put]
+func (u *AdminUserController) HandleUpdateUser(ctx *gin.Context) {
+	var req dto.UpdateAdminUserRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	user, err := u.adminUserSvc.UpdateUser(ctx.Param("userID"), currentUserID(ctx), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary List the Roles Admin Users Can Have
+// @Description Each role with the permissions it grants on the admin routes.
+// @Id listRoles
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} dto.RoleResponse
+// @Router /admin/roles [get]
+func (u *AdminUserController) HandleListRoles(ctx *gin.Context) {
+	roles := make([]dto.RoleResponse, 0, len(jwt.Roles()))
+	for _, role := range jwt.Roles() {
+		roles = append(roles, dto.RoleResponse{Role: role, Permissions: role.Permissions()})
+	}
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// @Summary Assign a Role to an Admin User
+// @Description Ends the sessions of the user, who signs in again with the permissions of the new role.
+// @Id assignRole
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param userID path string true "userID"
+// @Param body body dto.AssignRoleRequest true "body"
+// @Success 200 {object} model.AdminUser
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/users/{userID}/role [put]
+func (u *AdminUserController) HandleAssignRole(ctx *gin.Context) {
+	var req dto.AssignRoleRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	user, err := u.adminUserSvc.AssignRole(ctx.Param("userID"), currentUserID(ctx), req.Role)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+func NewAdminUserController(adminUserSvc service.AdminUserService) *AdminUserController {
+	return &AdminUserController{
+		adminUserSvc: adminUserSvc,
+	}
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Check an Attendee In
+// @Description Admits the attendee of a scanned ticket QR code. Tickets of unpaid or cancelled registrations
+// @Description and tickets reissued to someone else are refused; scanning a ticket again returns when it was first scanned.
+// @Id checkIn
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.CheckInRequest true "body"
+// @Success 200 {object} dto.CheckInResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/check-ins [post]
+func (u *RegistrationController) HandleCheckIn(ctx *gin.Context) {
+	var req dto.CheckInRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	checkIn, err := u.registrationSvc.CheckIn(req, currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, checkIn)
+}
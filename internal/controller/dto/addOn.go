@@ -0,0 +1,45 @@
+package dto
+
+// AddOnRequest orders an add-on by code.
+type AddOnRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Quantity int    `json:"quantity" binding:"min=1"`
+	// AccompanyPersons marks the add-ons bought for the accompanying persons
+	AccompanyPersons bool `json:"-"`
+}
+
+type CreateAddOnRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Capacity    int    `json:"capacity" binding:"min=0"` // 0 is unlimited
+}
+
+type UpdateAddOnRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Capacity    int    `json:"capacity" binding:"min=0"` // 0 is unlimited
+	Active      bool   `json:"active"`
+}
+
+type AddOnPriceRequest struct {
+	// Subtype is the registration period the price applies to, empty for every other period
+	Subtype string  `json:"subtype"`
+	FeeUSD  float64 `json:"fee_usd" binding:"gte=0"`
+	FeeVND  int64   `json:"fee_vnd" binding:"gte=0"`
+}
+
+type ReplaceAddOnPricesRequest struct {
+	Prices []AddOnPriceRequest `json:"prices" binding:"required,dive"`
+}
+
+// CatalogueAddOn is an add-on on sale with its price in the current period.
+type CatalogueAddOn struct {
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	FeeUSD      float64 `json:"fee_usd"`
+	FeeVND      int64   `json:"fee_vnd"`
+	Capacity    int     `json:"capacity"`  // 0 is unlimited
+	Remaining   int     `json:"remaining"` // units left, -1 when unlimited
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/model"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginResponse struct {
+	// Token goes into the session-key header of admin requests until ExpiresAt
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+	// RefreshToken renews the session once, until RefreshExpiresAt
+	RefreshToken     string           `json:"refresh_token"`
+	RefreshExpiresAt int64            `json:"refresh_expires_at"`
+	RefreshTokenID   string           `json:"-"`
+	User             *model.AdminUser `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type CreateAdminUserRequest struct {
+	Email    string   `json:"email" binding:"required,email"`
+	Name     string   `json:"name" binding:"max=100"`
+	Password string   `json:"password" binding:"required,min=10,max=72"`
+	Role     jwt.Role `json:"role" binding:"required,oneof=admin staff finance check_in"`
+}
+
+// UpdateAdminUserRequest changes the given fields of an admin user; fields left out are kept.
+type UpdateAdminUserRequest struct {
+	Name     *string   `json:"name" binding:"omitempty,max=100"`
+	Password *string   `json:"password" binding:"omitempty,min=10,max=72"`
+	Role     *jwt.Role `json:"role" binding:"omitempty,oneof=admin staff finance check_in"`
+	Active   *bool     `json:"active"`
+}
+
+type AssignRoleRequest struct {
+	Role jwt.Role `json:"role" binding:"required,oneof=admin staff finance check_in"`
+}
+
+// RoleResponse is a role that can be given to admin users and what it allows.
+type RoleResponse struct {
+	Role        jwt.Role         `json:"role"`
+	Permissions []jwt.Permission `json:"permissions"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// CheckInRequest is what the ticket QR code holds: the registration ID and, for reissued
+// tickets, the ticket query parameter.
+type CheckInRequest struct {
+	RegistrationID string `json:"registration_id" binding:"required"`
+	Ticket         string `json:"ticket"`
+}
+
+// CheckInResponse is what the volunteer at the door needs to see, without the attendee's other details.
+type CheckInResponse struct {
+	RegistrationID       string `json:"registration_id"`
+	Name                 string `json:"name"`
+	DoctorateDegree      string `json:"doctorate_degree"`
+	RegistrationCategory string `json:"registration_category"`
+	Institution          string `json:"institution"`
+	AttendsGalaDinner    bool   `json:"attends_gala_dinner"`
+	AccompanyPersons     int    `json:"accompany_persons"`
+	// AlreadyCheckedIn is set when the ticket had been scanned before, at CheckedInAt
+	AlreadyCheckedIn bool       `json:"already_checked_in"`
+	CheckedInAt      *time.Time `json:"checked_in_at"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+const (
+	ExchangeRateSourceProvider = "provider"
+	ExchangeRateSourceOverride = "override"
+)
+
+type ExchangeRateResponse struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Rate  float64 `json:"rate"`
+	// Source is "override" when the rate was set by an admin, "provider" otherwise
+	Source string `json:"source"`
+}
+
+type ExchangeRateOverrideRequest struct {
+	Rate float64 `json:"rate" binding:"required,gt=0"`
+}
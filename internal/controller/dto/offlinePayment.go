@@ -0,0 +1,32 @@
+package dto
+
+// OfflinePayment tells the attendee how to pay a registration outside OnePay.
+type OfflinePayment struct {
+	PaymentMethod string `json:"payment_method"`
+	PaymentStatus string `json:"payment_status"`
+	// Amount due, in VND for Vietnamese attendees and USD for everyone else
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// BankTransfer is the account to transfer to, for bank transfers only
+	BankTransfer *BankTransfer `json:"bank_transfer,omitempty"`
+}
+
+// BankTransfer is a transfer in VND that pays a registration, to make from any Vietnamese banking app.
+type BankTransfer struct {
+	BankName      string `json:"bank_name"`
+	BankBIN       string `json:"bank_bin"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+	Amount        int64  `json:"amount"`
+	// TransferReference is the memo the transfer must carry, it contains the registration ID
+	TransferReference string `json:"transfer_reference"`
+	// VietQRPayload is the EMVCo payload banking apps read, VietQRImage the same as a PNG data URI
+	VietQRPayload string `json:"vietqr_payload"`
+	VietQRImage   string `json:"vietqr_image"`
+}
+
+type ConfirmOfflinePaymentRequest struct {
+	// Amount received, in the currency of the registration; 0 is the amount due
+	Amount int64  `json:"amount" binding:"min=0"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// PeriodLayout is the local date-time format start_at and end_at are given in.
+const PeriodLayout = "2006-01-02T15:04:05"
+
+type PeriodRequest struct {
+	// Name is the RegistrationOption subtype sold during the period, e.g. EarlyBird
+	Name    string `json:"name" binding:"required"`
+	StartAt string `json:"start_at" binding:"required" example:"2025-09-01T00:00:00"`
+	EndAt   string `json:"end_at" binding:"required" example:"2025-11-01T00:00:00"`
+	// TimeZone start_at and end_at are read in, an IANA name such as Asia/Ho_Chi_Minh
+	TimeZone string `json:"time_zone" binding:"required"`
+}
+
+type ReplacePeriodsRequest struct {
+	Periods []PeriodRequest `json:"periods" binding:"required,dive"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"ashno-onepay/internal/model"
+	"time"
+)
+
+type CreatePromoCodeRequest struct {
+	Code string `json:"code" binding:"required,max=50"`
+	PromoCodeRequest
+}
+
+type PromoCodeRequest struct {
+	Description string             `json:"description"`
+	Type        model.DiscountType `json:"type" binding:"required,oneof=percentage fixed waiver"`
+	Percent     float64            `json:"percent" binding:"gte=0,lte=100"` // for percentage
+	AmountUSD   float64            `json:"amount_usd" binding:"gte=0"`      // for fixed
+	AmountVND   int64              `json:"amount_vnd" binding:"gte=0"`      // for fixed
+	MaxUses     int                `json:"max_uses" binding:"gte=0"`        // 0 is unlimited
+	ValidFrom   *time.Time         `json:"valid_from"`
+	ValidUntil  *time.Time         `json:"valid_until"`
+	// Categories the code is accepted for, every category when empty
+	Categories []string `json:"categories"`
+	// Email binds the code to one registrant
+	Email  string `json:"email" binding:"omitempty,email"`
+	Active bool   `json:"active"`
+}
+
+type UpdatePromoCodeRequest struct {
+	PromoCodeRequest
+}
@@ -18,14 +18,64 @@ type RegistrationRequest struct {
 	RegistrationOption string                  `json:"registration_option" binding:"required"`
 	AttendGalaDinner   bool                    `json:"attend_gala_dinner"`
 	AccompanyPersons   []model.AccompanyPerson `json:"accompany_persons"`
+	AddOns             []AddOnRequest          `json:"add_ons" binding:"dive"`
+	PromoCode          string                  `json:"promo_code"`
+	// PaymentMethod defaults to domestic cards for Vietnamese attendees and international cards otherwise.
+	// bank_transfer and cash are paid outside OnePay, bank_transfer in VND only.
+	PaymentMethod string `json:"payment_method" binding:"omitempty,oneof=onepay_domestic onepay_international bank_transfer cash"`
 }
 
 type RegistrationResponse struct {
 	PaymentURL string `json:"payment_url"`
 	UserID     string `json:"user_id"`
+	// PaymentStatus is done when there was nothing to pay, e.g. the fee was waived, and PaymentURL is empty
+	PaymentStatus string `json:"payment_status"`
+	// BankTransfer is a VietQR transfer paying the registration, for Vietnamese attendees: the way to pay
+	// with the bank_transfer method and an alternative to PaymentURL with the OnePay ones
+	BankTransfer *BankTransfer `json:"bank_transfer,omitempty"`
 }
 
 type AccompanyPersonRegistrationRequest struct {
 	Email            string                  `json:"email" binding:"required"`
 	AccompanyPersons []model.AccompanyPerson `json:"accompany_persons" binding:"required,dive"`
 }
+
+type RefundRequest struct {
+	// PaymentTransactionID defaults to the registration payment when empty
+	PaymentTransactionID string `json:"payment_transaction_id"`
+	// Amount in the transaction currency, 0 refunds everything still refundable
+	Amount int64  `json:"amount" binding:"min=0"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PaymentReturnResult is a verified OnePay return redirect as shown to the payer.
+type PaymentReturnResult struct {
+	RegistrationID string `json:"registration_id"`
+	GroupID        string `json:"group_id"`
+	Result         string `json:"result"`
+	ResponseCode   string `json:"response_code"`
+	Message        string `json:"message"`
+	MerchTxnRef    string `json:"merch_txn_ref"`
+	OrderInfo      string `json:"order_info"`
+}
+
+// GroupRegistrationRequest registers many attendees paid for by one sponsor.
+type GroupRegistrationRequest struct {
+	Sponsor      string `json:"sponsor" binding:"required"`
+	ContactName  string `json:"contact_name" binding:"required"`
+	ContactEmail string `json:"contact_email" binding:"required,email"`
+	ContactPhone string `json:"contact_phone"`
+	// Nationality of the sponsor, vn to pay in VND
+	Nationality   string `json:"nationality"`
+	PaymentMethod string `json:"payment_method" binding:"omitempty,oneof=onepay_domestic onepay_international"`
+	// Attendees are registered as they would register themselves; their sponsor and payment method are the group's
+	Attendees []RegistrationRequest `json:"attendees" binding:"required,min=1,max=200,dive"`
+}
+
+type GroupRegistrationResponse struct {
+	PaymentURL      string   `json:"payment_url"`
+	GroupID         string   `json:"group_id"`
+	RegistrationIDs []string `json:"registration_ids"`
+	// PaymentStatus is done when there was nothing to pay and PaymentURL is empty
+	PaymentStatus string `json:"payment_status"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+// CancellationRequest asks to cancel a paid registration, refunded as the refund policy says.
+type CancellationRequest struct {
+	// Email of the registration, confirming the attendee asks
+	Email  string `json:"email" binding:"required,email"`
+	Reason string `json:"reason"`
+}
+
+// SubstitutionRequest asks to hand a paid registration over to another attendee.
+type SubstitutionRequest struct {
+	// Email of the registration, confirming the attendee asks
+	Email      string          `json:"email" binding:"required,email"`
+	Reason     string          `json:"reason"`
+	Substitute AttendeeRequest `json:"substitute" binding:"required"`
+}
+
+type AttendeeRequest struct {
+	DoctorateDegree string `json:"doctorate_degree" binding:"required"`
+	FirstName       string `json:"first_name" binding:"required"`
+	MiddleName      string `json:"middle_name"`
+	LastName        string `json:"last_name"`
+	DateOfBirth     string `json:"date_of_birth"`
+	Institution     string `json:"institution"`
+	Email           string `json:"email" binding:"required,email"`
+	PhoneNumber     string `json:"phone_number"`
+}
+
+type DecideChangeRequest struct {
+	Note string `json:"note"`
+	// RefundAmount replaces the amount the refund policy grants a cancellation, in its currency
+	RefundAmount *int64 `json:"refund_amount" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// ImportRegistrationsRequest is the form sent with a file of attendees.
+type ImportRegistrationsRequest struct {
+	// PaymentStatus of the imported registrations: done for attendees who paid offline,
+	// pending to generate a payment link for each of them
+	PaymentStatus string `form:"payment_status" binding:"required,oneof=done pending"`
+	// DryRun only validates the file
+	DryRun bool `form:"dry_run"`
+}
+
+type ImportRowResult struct {
+	Row            int      `json:"row"` // row number in the file, the header being row 1
+	Email          string   `json:"email"`
+	RegistrationID string   `json:"registration_id"`
+	PaymentStatus  string   `json:"payment_status"`
+	PaymentURL     string   `json:"payment_url"`
+	Errors         []string `json:"errors"`
+}
+
+type ImportRegistrationsResponse struct {
+	Total    int               `json:"total"`
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	Rows     []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ManageLinkRequest asks for a magic link to edit the registration made with Email.
+type ManageLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// UpdateDetailsRequest corrects the attendee details of a registration. Fields left out are kept;
+// nothing that changes the price, the payment or the ticket holder can be edited.
+type UpdateDetailsRequest struct {
+	DoctorateDegree *string `json:"doctorate_degree" binding:"omitempty,min=1,max=100"`
+	FirstName       *string `json:"first_name" binding:"omitempty,min=1,max=100"`
+	MiddleName      *string `json:"middle_name" binding:"omitempty,max=100"`
+	LastName        *string `json:"last_name" binding:"omitempty,max=100"`
+	DateOfBirth     *string `json:"date_of_birth" binding:"omitempty,datetime=2006-01-02"`
+	Institution     *string `json:"institution" binding:"omitempty,max=255"`
+	PhoneNumber     *string `json:"phone_number" binding:"omitempty,max=20"`
+}
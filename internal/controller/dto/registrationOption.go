@@ -0,0 +1,49 @@
+package dto
+
+import "ashno-onepay/internal/model"
+
+type CreateRegistrationOptionRequest struct {
+	Category string  `json:"category" binding:"required"`
+	Subtype  string  `json:"subtype"` // the registration period name, empty for options sold in every period
+	FeeUSD   float64 `json:"fee_usd" binding:"gte=0"`
+	FeeVND   int64   `json:"fee_vnd" binding:"gte=0"`
+}
+
+type UpdateRegistrationOptionRequest struct {
+	FeeUSD float64 `json:"fee_usd" binding:"gte=0"`
+	FeeVND int64   `json:"fee_vnd" binding:"gte=0"`
+}
+
+// RegistrationCatalogueResponse is the full public price list.
+type RegistrationCatalogueResponse struct {
+	RegistrationOpen bool                `json:"registration_open"`
+	CurrentPeriod    *model.Period       `json:"current_period"`
+	Periods          []*model.Period     `json:"periods"`
+	Categories       []CatalogueCategory `json:"categories"`
+	AddOns           []CatalogueAddOn    `json:"add_ons"`
+}
+
+type CatalogueCategory struct {
+	Category  string            `json:"category"`
+	Options   []CatalogueOption `json:"options"`
+	Capacity  int               `json:"capacity"`  // 0 is unlimited
+	Remaining int               `json:"remaining"` // -1 when unlimited, 0 when registrants can only join the waitlist
+}
+
+type CategoryCapacityRequest struct {
+	Category string `json:"category" binding:"required"`
+	Capacity int    `json:"capacity" binding:"min=0"` // 0 is unlimited
+}
+
+type ReplaceCategoryCapacitiesRequest struct {
+	Capacities []CategoryCapacityRequest `json:"capacities" binding:"required,dive"`
+}
+
+type CatalogueOption struct {
+	ID      string  `json:"id"`
+	Subtype string  `json:"subtype"`
+	FeeUSD  float64 `json:"fee_usd"`
+	FeeVND  int64   `json:"fee_vnd"`
+	// Current is true for the option sold in the current period
+	Current bool `json:"current"`
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateController struct {
+	rateSvc service.RateService
+}
+
+// @Summary Get the USD to VND Rate Applied to New Checkouts
+// @Id getExchangeRate
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {object} dto.ExchangeRateResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/exchange-rate [get]
+func (u *ExchangeRateController) HandleGetExchangeRate(ctx *gin.Context) {
+	rate, err := u.rateSvc.GetExchangeRate()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rate)
+}
+
+// @Summary Override the USD to VND Rate
+// @Id setExchangeRate
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.ExchangeRateOverrideRequest true "body"
+// @Success 200 {object} model.ExchangeRate
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/exchange-rate [put]
+func (u *ExchangeRateController) HandleSetExchangeRate(ctx *gin.Context) {
+	var req dto.ExchangeRateOverrideRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	rate, err := u.rateSvc.SetOverride(req.Rate, currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rate)
+}
+
+// @Summary Remove the USD to VND Rate Override
+// @Id clearExchangeRate
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 204
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/exchange-rate [delete]
+func (u *ExchangeRateController) HandleClearExchangeRate(ctx *gin.Context) {
+	if err := u.rateSvc.ClearOverride(); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func NewExchangeRateController(rateSvc service.RateService) *ExchangeRateController {
+	return &ExchangeRateController{
+		rateSvc: rateSvc,
+	}
+}
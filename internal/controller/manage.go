@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/service"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt"
+)
+
+// ManageController lets attendees correct their own details through a link emailed to them.
+type ManageController struct {
+	registrationSvc service.RegistrationService
+	issuer          jwt.Issuer
+	validator       jwt.Validator
+	config          *config.Config
+}
+
+// @Summary Email a Link to Manage a Registration
+// @Description Sends a short-lived link to edit the registration made with the email. The answer is the same
+// @Description whether or not the email is registered.
+// @Id requestManageLink
+// @Tags register
+// @version 1.0
+// @Param body body dto.ManageLinkRequest true "body"
+// @Success 204
+// @Failure 400 {object} errors.AppError
+// @Router /register/manage-link [post]
+func (u *ManageController) HandleRequestManageLink(ctx *gin.Context) {
+	var req dto.ManageLinkRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	reg, err := u.registrationSvc.GetManageableRegistration(req.Email)
+	if err != nil {
+		log.Println("manage link lookup failed: ", err.Error())
+	}
+	if reg != nil {
+		token, err := u.issuer.IssueManage(ctx, &jwt.ManageClaims{
+			StandardClaims: gojwt.StandardClaims{
+				Subject:   reg.Id,
+				IssuedAt:  time.Now().Unix(),
+				ExpiresAt: time.Now().Add(u.config.Manage.GetLinkTTL()).Unix(),
+			},
+			Email: reg.Email,
+		})
+		if err != nil {
+			handleError(ctx, errors.ErrInternal.Wrap(err))
+			return
+		}
+		u.registrationSvc.SendManageLink(reg, u.managePage(token))
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Get the Registration a Manage Link Was Sent For
+// @Id getManagedRegistration
+// @Tags register
+// @version 1.0
+// @Param token query string true "token from the manage link"
+// @Success 200 {object} model.Registration
+// @Failure 401 {object} errors.AppError
+// @Router /register/manage [get]
+func (u *ManageController) HandleGetManagedRegistration(ctx *gin.Context) {
+	claims, err := u.validator.ValidateManage(ctx, ctx.Query("token"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	reg, err := u.registrationSvc.GetManagedRegistration(claims.Subject, claims.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reg)
+}
+
+// @Summary Correct Attendee Details
+// @Description Edits the name, date of birth, institution or phone number of a registration. The category,
+// @Description add-ons, email and payment cannot be changed here; every change is recorded on the audit log.
+// @Id updateManagedRegistration
+// @Tags register
+// @version 1.0
+// @Param token query string true "token from the manage link"
+// @Param body body dto.UpdateDetailsRequest true "body"
+// @Success 200 {object} model.Registration
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /register/manage [patch]
+func (u *ManageController) HandleUpdateManagedRegistration(ctx *gin.Context) {
+	claims, err := u.validator.ValidateManage(ctx, ctx.Query("token"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req dto.UpdateDetailsRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	reg, err := u.registrationSvc.UpdateDetails(claims.Subject, claims.Email, req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reg)
+}
+
+// managePage returns the configured manage page with the token. Without one, the attendee is sent
+// to the manage page under OnePay.ReturnURL.
+func (u *ManageController) managePage(token string) string {
+	page := u.config.Manage.PageURL
+	if page == "" {
+		page = u.config.OnePay.ReturnURL + "/manage"
+	}
+	parsed, err := url.Parse(page)
+	if err != nil {
+		return page + "?token=" + url.QueryEscape(token)
+	}
+	values := parsed.Query()
+	values.Set("token", token)
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
+
+func NewManageController(
+	registrationSvc service.RegistrationService,
+	issuer jwt.Issuer,
+	validator jwt.Validator,
+	config *config.Config,
+) *ManageController {
+	return &ManageController{
+		registrationSvc: registrationSvc,
+		issuer:          issuer,
+		validator:       validator,
+		config:          config,
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get How to Pay a Registration by Bank Transfer or Cash
+// @Id getOfflinePayment
+// @Tags register
+// @version 1.0
+// @Param registerID path string true "registerID"
+// @Success 200 {object} dto.OfflinePayment
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/{registerID}/offline-payment [get]
+func (u *RegistrationController) HandleGetOfflinePayment(ctx *gin.Context) {
+	payment, err := u.registrationSvc.GetOfflinePayment(ctx.Param("registerID"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, payment)
+}
+
+// @Summary Confirm Receipt of a Bank Transfer or Cash Payment
+// @Description Marks the registration paid and sends the confirmation email, as a successful OnePay payment does.
+// @Id confirmOfflinePayment
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param registerID path string true "registerID"
+// @Param body body dto.ConfirmOfflinePaymentRequest true "body"
+// @Success 200 {object} model.Registration
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registrations/{registerID}/offline-payment/confirm [post]
+func (u *RegistrationController) HandleConfirmOfflinePayment(ctx *gin.Context) {
+	var req dto.ConfirmOfflinePaymentRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	reg, err := u.registrationSvc.ConfirmOfflinePayment(ctx.Param("registerID"), req, currentUserID(ctx), ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reg)
+}
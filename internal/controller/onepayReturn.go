@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/onepay"
+	"ashno-onepay/internal/service"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt"
+)
+
+// OnePayReturnController handles the payer's browser coming back from the paygate.
+type OnePayReturnController struct {
+	registrationSvc service.RegistrationService
+	issuer          jwt.Issuer
+	validator       jwt.Validator
+	config          *config.Config
+}
+
+// @Summary OnePay Return URL Handler
+// @Description Verifies the paygate redirect and sends the payer to the success or failure page
+// @Description with a signed `token` and the `result` query parameters.
+// @Id onePayReturn
+// @Tags register
+// @version 1.0
+// @Success 302
+// @Router /onepay/return [get]
+func (u *OnePayReturnController) HandleOnePayReturn(ctx *gin.Context) {
+	result, err := u.registrationSvc.OnePayVerifyReturn(ctx.Request.URL)
+	if err != nil {
+		log.Println("OnePay return verification failed: ", err.Error())
+		ctx.Redirect(http.StatusFound, u.resultPage(false, "", url.Values{"result": {string(onepay.OutcomeError)}}))
+		return
+	}
+
+	token, err := u.issuer.IssuePaymentResult(ctx, &jwt.PaymentResultClaims{
+		StandardClaims: gojwt.StandardClaims{
+			Subject:   result.RegistrationID,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(u.config.Payment.GetResultTokenTTL()).Unix(),
+		},
+		GroupID:      result.GroupID,
+		Result:       result.Result,
+		ResponseCode: result.ResponseCode,
+		MerchTxnRef:  result.MerchTxnRef,
+		OrderInfo:    result.OrderInfo,
+	})
+	if err != nil {
+		handleError(ctx, errors.ErrInternal.Wrap(err))
+		return
+	}
+	succeeded := result.Result == string(onepay.OutcomeSuccess)
+	ctx.Redirect(http.StatusFound, u.resultPage(succeeded, result.RegistrationID, url.Values{
+		"result": {result.Result},
+		"token":  {token},
+	}))
+}
+
+// @Summary Read a Payment Result Token
+// @Id getPaymentResult
+// @Tags register
+// @version 1.0
+// @Param token query string true "token from the result page URL"
+// @Success 200 {object} jwt.PaymentResultClaims
+// @Failure 401 {object} errors.AppError
+// @Router /onepay/result [get]
+func (u *OnePayReturnController) HandleGetPaymentResult(ctx *gin.Context) {
+	claims, err := u.validator.ValidatePaymentResult(ctx, ctx.Query("token"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, claims)
+}
+
+// resultPage returns the configured page for the outcome. Without one, the payer is sent
+// back to the registration page under OnePay.ReturnURL as before this handler existed.
+func (u *OnePayReturnController) resultPage(succeeded bool, registrationID string, query url.Values) string {
+	page := u.config.Payment.FailureURL
+	if succeeded {
+		page = u.config.Payment.SuccessURL
+	}
+	if page == "" {
+		page = u.config.OnePay.ReturnURL
+		if registrationID != "" {
+			page += "/" + registrationID
+		}
+	}
+	parsed, err := url.Parse(page)
+	if err != nil {
+		return page
+	}
+	values := parsed.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
+
+func NewOnePayReturnController(
+	registrationSvc service.RegistrationService,
+	issuer jwt.Issuer,
+	validator jwt.Validator,
+	config *config.Config,
+) *OnePayReturnController {
+	return &OnePayReturnController{
+		registrationSvc: registrationSvc,
+		issuer:          issuer,
+		validator:       validator,
+		config:          config,
+	}
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodController struct {
+	periodSvc service.PeriodService
+}
+
+// @Summary List Registration Periods
+// @Id listRegistrationPeriods
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.Period
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-periods [get]
+func (u *PeriodController) HandleListPeriods(ctx *gin.Context) {
+	periods, err := u.periodSvc.ListPeriods()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, periods)
+}
+
+// @Summary Replace the Registration Periods
+// @Description Periods must follow each other without overlaps or gaps.
+// @Id replaceRegistrationPeriods
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.ReplacePeriodsRequest true "body"
+// @Success 200 {array} model.Period
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-periods [put]
+func (u *PeriodController) HandleReplacePeriods(ctx *gin.Context) {
+	var req dto.ReplacePeriodsRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	periods, err := u.periodSvc.ReplacePeriods(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, periods)
+}
+
+func NewPeriodController(periodSvc service.PeriodService) *PeriodController {
+	return &PeriodController{
+		periodSvc: periodSvc,
+	}
+}
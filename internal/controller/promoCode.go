@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromoCodeController struct {
+	promoCodeSvc service.PromoCodeService
+}
+
+// @Summary List All Promo Codes and How Often They Were Used
+// @Id listPromoCodes
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.PromoCode
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/promo-codes [get]
+func (u *PromoCodeController) HandleListPromoCodes(ctx *gin.Context) {
+	promoCodes, err := u.promoCodeSvc.ListPromoCodes()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, promoCodes)
+}
+
+// @Summary Create a Promo Code
+// @Id createPromoCode
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.CreatePromoCodeRequest true "body"
+// @Success 200 {object} model.PromoCode
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/promo-codes [post]
+func (u *PromoCodeController) HandleCreatePromoCode(ctx *gin.Context) {
+	var req dto.CreatePromoCodeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	promoCode, err := u.promoCodeSvc.CreatePromoCode(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, promoCode)
+}
+
+// @Summary Update or Deactivate a Promo Code
+// @Description Registrations already made with the code keep their discount.
+// @Id updatePromoCode
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param promoCodeID path string true "promoCodeID"
+// @Param body body dto.UpdatePromoCodeRequest true "body"
+// @Success 200 {object} model.PromoCode
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/promo-codes/{promoCodeID} [put]
+func (u *PromoCodeController) HandleUpdatePromoCode(ctx *gin.Context) {
+	var req dto.UpdatePromoCodeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	promoCode, err := u.promoCodeSvc.UpdatePromoCode(ctx.Param("promoCodeID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, promoCode)
+}
+
+func NewPromoCodeController(promoCodeSvc service.PromoCodeService) *PromoCodeController {
+	return &PromoCodeController{
+		promoCodeSvc: promoCodeSvc,
+	}
+}
@@ -44,10 +44,28 @@ func (u *RegistrationController) HandleRegister(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, dto.RegistrationResponse{
-		PaymentURL: url,
-		UserID:     userID,
-	})
+	response := dto.RegistrationResponse{
+		PaymentURL:    url,
+		UserID:        userID,
+		PaymentStatus: paymentStatus(url),
+	}
+	// bank transfers and cash have no payment URL, they stay pending until an admin confirms receipt
+	if req.PaymentMethod != "" && !model.PaymentMethod(req.PaymentMethod).IsOnePay() {
+		payment, err := u.registrationSvc.GetOfflinePayment(userID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		response.PaymentStatus = payment.PaymentStatus
+	}
+	if response.PaymentStatus == string(model.PaymentStatusPending) {
+		response.BankTransfer, err = u.registrationSvc.GetBankTransfer(userID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, response)
 }
 
 // @Summary Get Registration Information by ID
@@ -55,6 +73,7 @@ func (u *RegistrationController) HandleRegister(ctx *gin.Context) {
 // @Tags register
 // @version 1.0
 // @Param registerID path string true "registerID"
+// @Param ticket query string false "ticket code of the QR code, for reissued tickets"
 // @Success 200 {object} model.Registration
 // @Failure 400 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
@@ -67,6 +86,11 @@ func (u *RegistrationController) HandlerGetRegistrationInfo(ctx *gin.Context) {
 		handleError(ctx, errors.ErrInternal.Wrap(err))
 		return
 	}
+	// a QR code sent before the ticket was reissued no longer matches
+	if !reg.HasTicket(ctx.Query("ticket")) {
+		handleError(ctx, errors.ErrNotFound.Reform("ticket is no longer valid"))
+		return
+	}
 
 	ctx.JSON(http.StatusOK, reg)
 }
@@ -81,6 +105,10 @@ func (u *RegistrationController) HandlerGetRegistrationInfo(ctx *gin.Context) {
 // @Router /onepay/ipn [get]
 func (u *RegistrationController) HandlerOnePayIPN(ctx *gin.Context) {
 	err := u.registrationSvc.OnePayVerifySecureHash(ctx.Request.URL)
+	if appErr, ok := err.(errors.AppError); ok && appErr.Code == errors.ErrPaymentExpired.Code {
+		ctx.String(http.StatusOK, "responsecode=0&desc=confirm-fail-expired")
+		return
+	}
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -101,11 +129,13 @@ func (u *RegistrationController) HandlerGetOption(ctx *gin.Context) {
 	attendGalaDinner := ctx.Query("attend_gala_dinner") == "true"
 	numberAccompanyPersons, _ := strconv.Atoi(ctx.Query("numbers_accompany_persons"))
 	email := ctx.Query("email")
+	promoCode := ctx.Query("promo_code")
 	option, err := u.registrationSvc.GetRegistrationOption(model.RegistrationOptionFilter{
 		Category:               registrationOption,
 		AttendGalaDinner:       attendGalaDinner,
 		NumberAccompanyPersons: numberAccompanyPersons,
 		Email:                  email,
+		PromoCode:              promoCode,
 	})
 	if err != nil {
 		handleError(ctx, err)
@@ -136,7 +166,8 @@ func (u *RegistrationController) HandleRegisterAccompanyPersons(ctx *gin.Context
 		return
 	}
 	ctx.JSON(http.StatusOK, dto.RegistrationResponse{
-		PaymentURL: paymentURL,
+		PaymentURL:    paymentURL,
+		PaymentStatus: paymentStatus(paymentURL),
 	})
 }
 
@@ -146,7 +177,9 @@ func (u *RegistrationController) HandleRegisterAccompanyPersons(ctx *gin.Context
 // @version 1.0
 // @Param start_time query string false "Start time (YYYY-MM-DD)"
 // @Param end_time query string false "End time (YYYY-MM-DD)"
+// @Security SessionKey
 // @Success 200 {file} xlsx "XLSX file"
+// @Failure 401 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /register/file [get]
 func (u *RegistrationController) HandleGetFile(ctx *gin.Context) {
@@ -179,10 +212,10 @@ func (u *RegistrationController) HandleGetFile(ctx *gin.Context) {
 	f := excelize.NewFile()
 	sheet := "Registrations"
 	f.SetSheetName(f.GetSheetName(0), sheet)
-	headers := []string{"VerifyLink", "Category", "Nationality", "DoctorateDegree", "FirstName",
+	headers := []string{"VerifyLink", "Category", "RegistrationOption", "Nationality", "DoctorateDegree", "FirstName",
 		"MiddleName", "LastName", "FullName", "DateOfBirth", "Institution", 
 		"Email", "PhoneNumber", "Sponsor", "PaymentStatus", "RegistrationTime", 
-		"AttendGalaDinner", "AccompanyPersons", "PaymentAmount" }
+		"AttendGalaDinner", "AccompanyPersons", "AddOns", "PaymentAmount", "PromoCode", "PaymentMethod", "TransferReference" }
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		f.SetCellValue(sheet, cell, h)
@@ -202,19 +235,28 @@ func (u *RegistrationController) HandleGetFile(ctx *gin.Context) {
 		f.SetCellValue(sheet, verifyLinkCell, "VerifyLink")
 		f.SetCellHyperLink(sheet, verifyLinkCell, verifyURL, "External")
 		attendGalaDinner := "No"
-		if model.RegistrationCategory(reg.RegistrationOption.Category) == model.DoctorAndDinnerCategory || 
-		model.RegistrationCategory(reg.RegistrationOption.Category) == model.StudentAndDinnerCategory {
+		if reg.AttendsGalaDinner() {
 			attendGalaDinner = "Yes"
 		}
+		var addOnList []string
+		for _, item := range reg.LineItems {
+			if item.IsVoid() {
+				continue
+			}
+			addOnList = append(addOnList, fmt.Sprintf("%s x%d", item.Name, item.Quantity))
+		}
+		addOnStr := strings.Join(addOnList, "\n")
+		feeUSD, feeVND := reg.Fee()
 		var paymentAmount string
 		if reg.Nationality == model.NationalityVietNam {
-			paymentAmount = fmt.Sprintf("%d VND", reg.RegistrationOption.FeeVND + int64(len(reg.AccompanyPersons)) * model.GalaDinnerOnlyOption.FeeVND)
+			paymentAmount = fmt.Sprintf("%d VND", feeVND)
 		} else {
-			paymentAmount = fmt.Sprintf("%d USD", int(reg.RegistrationOption.FeeUSD + float64(len(reg.AccompanyPersons)) * model.GalaDinnerOnlyOption.FeeUSD))
+			paymentAmount = fmt.Sprintf("%d USD", int(feeUSD))
 		}
 		row := []interface{}{
 			// skip the first column, already set
 			reg.RegistrationCategory,
+			reg.RegistrationOption.Category,
 			model.GetCountryName(reg.Nationality),
 			reg.DoctorateDegree,
 			reg.FirstName,
@@ -230,7 +272,11 @@ func (u *RegistrationController) HandleGetFile(ctx *gin.Context) {
 			reg.CreatedAt.Format(time.DateTime),
 			attendGalaDinner,
 			accompanyStr,
+			addOnStr,
 			paymentAmount,
+			reg.PromoCode,
+			reg.PaymentMethod,
+			reg.TransferReference,
 		}
 		for colIdx, val := range row {
 			cell, _ := excelize.CoordinatesToCellName(colIdx+2, rowIdx+2)
@@ -247,6 +293,86 @@ func (u *RegistrationController) HandleGetFile(ctx *gin.Context) {
 	}
 }
 
+// @Summary List Payment Attempts of a Registration
+// @Id listPaymentTransactions
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param registerID path string true "registerID"
+// @Success 200 {array} model.PaymentTransaction
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registrations/{registerID}/payment-transactions [get]
+func (u *RegistrationController) HandleGetPaymentTransactions(ctx *gin.Context) {
+	registerID := ctx.Param("registerID")
+
+	transactions, err := u.registrationSvc.GetPaymentTransactions(registerID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, transactions)
+}
+
+// @Summary Refund a Registration Payment through OnePay
+// @Id refundRegistration
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param registerID path string true "registerID"
+// @Param body body dto.RefundRequest true "body"
+// @Success 200 {object} model.Refund
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registrations/{registerID}/refunds [post]
+func (u *RegistrationController) HandleRefund(ctx *gin.Context) {
+	var req dto.RefundRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	registerID := ctx.Param("registerID")
+
+	refund, err := u.registrationSvc.Refund(registerID, req, currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, refund)
+}
+
+// @Summary Get a New Payment URL for an Unpaid Registration
+// @Id renewPaymentURL
+// @Tags register
+// @version 1.0
+// @Param registerID path string true "registerID"
+// @Success 200 {object} dto.RegistrationResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/{registerID}/payment-url [post]
+func (u *RegistrationController) HandleRenewPaymentURL(ctx *gin.Context) {
+	registerID := ctx.Param("registerID")
+
+	url, err := u.registrationSvc.RenewPaymentURL(registerID, ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.RegistrationResponse{
+		PaymentURL:    url,
+		UserID:        registerID,
+		PaymentStatus: paymentStatus(url),
+	})
+}
+
+// paymentStatus is done when no payment URL was needed because nothing was left to pay.
+func paymentStatus(paymentURL string) string {
+	if paymentURL == "" {
+		return string(model.PaymentStatusDone)
+	}
+	return string(model.PaymentStatusPending)
+}
+
 func NewRegistrationController(registrationSvc service.RegistrationService, config *config.Config) *RegistrationController {
 	return &RegistrationController{
 		registrationSvc: registrationSvc,
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Ask to Cancel a Paid Registration
+// @Description The refund follows the cancellation policy on the day of the request. Nothing changes until an admin approves it.
+// @Id requestCancellation
+// @Tags register
+// @version 1.0
+// @Param registerID path string true "registerID"
+// @Param body body dto.CancellationRequest true "body"
+// @Success 200 {object} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/{registerID}/cancellation [post]
+func (u *RegistrationController) HandleRequestCancellation(ctx *gin.Context) {
+	var req dto.CancellationRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	change, err := u.registrationSvc.RequestCancellation(ctx.Param("registerID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, change)
+}
+
+// @Summary Ask to Transfer a Paid Registration to Another Attendee
+// @Description The substitute receives a new ticket once an admin approves the request; the current QR code stops working.
+// @Id requestSubstitution
+// @Tags register
+// @version 1.0
+// @Param registerID path string true "registerID"
+// @Param body body dto.SubstitutionRequest true "body"
+// @Success 200 {object} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/{registerID}/substitution [post]
+func (u *RegistrationController) HandleRequestSubstitution(ctx *gin.Context) {
+	var req dto.SubstitutionRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	change, err := u.registrationSvc.RequestSubstitution(ctx.Param("registerID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, change)
+}
+
+// @Summary List the Cancellation and Substitution Requests of a Registration
+// @Id listRegistrationChanges
+// @Tags register
+// @version 1.0
+// @Param registerID path string true "registerID"
+// @Success 200 {array} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/{registerID}/changes [get]
+func (u *RegistrationController) HandleListRegistrationChanges(ctx *gin.Context) {
+	changes, err := u.registrationSvc.ListRegistrationChanges(ctx.Param("registerID"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, changes)
+}
+
+// @Summary List Cancellation and Substitution Requests
+// @Id listChanges
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param status query string false "pending, approved or rejected; every request when empty"
+// @Success 200 {array} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-changes [get]
+func (u *RegistrationController) HandleListChanges(ctx *gin.Context) {
+	changes, err := u.registrationSvc.ListChanges(ctx.Query("status"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, changes)
+}
+
+// @Summary Approve a Cancellation or Substitution Request
+// @Description A cancellation is refunded through OnePay, or recorded to refund by hand when paid outside OnePay. A substitution hands the registration over and emails the substitute a new ticket.
+// @Id approveChange
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param changeID path string true "changeID"
+// @Param body body dto.DecideChangeRequest true "body"
+// @Success 200 {object} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-changes/{changeID}/approve [post]
+func (u *RegistrationController) HandleApproveChange(ctx *gin.Context) {
+	var req dto.DecideChangeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	change, err := u.registrationSvc.ApproveChange(ctx.Param("changeID"), req, currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, change)
+}
+
+// @Summary Reject a Cancellation or Substitution Request
+// @Id rejectChange
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param changeID path string true "changeID"
+// @Param body body dto.DecideChangeRequest true "body"
+// @Success 200 {object} model.RegistrationChange
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-changes/{changeID}/reject [post]
+func (u *RegistrationController) HandleRejectChange(ctx *gin.Context) {
+	var req dto.DecideChangeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	change, err := u.registrationSvc.RejectChange(ctx.Param("changeID"), req, currentUserID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, change)
+}
+
+// @Summary Get the Audit Log of a Registration
+// @Id getAuditLog
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param registerID path string true "registerID"
+// @Success 200 {array} model.AuditLog
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registrations/{registerID}/audit-log [get]
+func (u *RegistrationController) HandleGetAuditLog(ctx *gin.Context) {
+	entries, err := u.registrationSvc.GetAuditLog(ctx.Param("registerID"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Register a Group of Attendees Paid by a Sponsor
+// @Description Every attendee gets a registration of their own; the sponsor pays for all of them with one payment URL.
+// @Id registerGroup
+// @Tags register
+// @version 1.0
+// @Param body body dto.GroupRegistrationRequest true "body"
+// @Success 200 {object} dto.GroupRegistrationResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/groups [post]
+func (u *RegistrationController) HandleRegisterGroup(ctx *gin.Context) {
+	var req dto.GroupRegistrationRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	response, err := u.registrationSvc.RegisterGroup(req, ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Summary Get a Sponsor Group and Its Registrations
+// @Id getRegistrationGroup
+// @Tags register
+// @version 1.0
+// @Param groupID path string true "groupID"
+// @Success 200 {object} model.RegistrationGroup
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/groups/{groupID} [get]
+func (u *RegistrationController) HandleGetGroup(ctx *gin.Context) {
+	group, err := u.registrationSvc.GetGroup(ctx.Param("groupID"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, group)
+}
+
+// @Summary Get a New Payment URL for the Unpaid Registrations of a Group
+// @Id renewGroupPaymentURL
+// @Tags register
+// @version 1.0
+// @Param groupID path string true "groupID"
+// @Success 200 {object} dto.GroupRegistrationResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/groups/{groupID}/payment-url [post]
+func (u *RegistrationController) HandleRenewGroupPaymentURL(ctx *gin.Context) {
+	groupID := ctx.Param("groupID")
+
+	url, err := u.registrationSvc.RenewGroupPaymentURL(groupID, ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.GroupRegistrationResponse{
+		PaymentURL:    url,
+		GroupID:       groupID,
+		PaymentStatus: paymentStatus(url),
+	})
+}
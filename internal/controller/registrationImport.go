@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// @Summary Import Registrations from an XLSX or CSV File
+// @Description The file has the columns of the registration export. Every row is validated like a registration request and reported with its errors; the valid rows are registered as paid offline or pending with a payment URL.
+// @Id importRegistrations
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Accept multipart/form-data
+// @Param file formData file true "XLSX or CSV file"
+// @Param payment_status formData string true "done or pending"
+// @Param dry_run formData bool false "only validate the file"
+// @Success 200 {object} dto.ImportRegistrationsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registrations/import [post]
+func (u *RegistrationController) HandleImportRegistrations(ctx *gin.Context) {
+	var req dto.ImportRegistrationsRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("form binding failed"))
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("file is required"))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("failed to open file"))
+		return
+	}
+	defer file.Close()
+
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".xlsx":
+		records, err = readXLSXRecords(file)
+	case ".csv":
+		records, err = readCSVRecords(file)
+	default:
+		handleError(ctx, errors.ErrBadRequest.Reform("file must be .xlsx or .csv"))
+		return
+	}
+	if err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("failed to read file"))
+		return
+	}
+
+	response, err := u.registrationSvc.ImportRegistrations(records, req, currentUserID(ctx), ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// readXLSXRecords reads the rows of the first sheet.
+func readXLSXRecords(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.GetRows(f.GetSheetName(0))
+}
+
+func readCSVRecords(r io.Reader) ([][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// spreadsheet applications write a byte order mark before the header
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
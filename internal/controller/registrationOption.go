@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RegistrationOptionController struct {
+	registrationOptionSvc service.RegistrationOptionService
+}
+
+// @Summary List Every Registration Option on Sale
+// @Id getRegistrationCatalogue
+// @Tags register
+// @version 1.0
+// @Success 200 {object} dto.RegistrationCatalogueResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/options [get]
+func (u *RegistrationOptionController) HandleGetCatalogue(ctx *gin.Context) {
+	catalogue, err := u.registrationOptionSvc.GetCatalogue()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, catalogue)
+}
+
+// @Summary List All Registration Options and Their Versions
+// @Id listRegistrationOptions
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.RegistrationOption
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-options [get]
+func (u *RegistrationOptionController) HandleListOptions(ctx *gin.Context) {
+	options, err := u.registrationOptionSvc.ListOptions()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, options)
+}
+
+// @Summary Create a Registration Option
+// @Id createRegistrationOption
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.CreateRegistrationOptionRequest true "body"
+// @Success 200 {object} model.RegistrationOption
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-options [post]
+func (u *RegistrationOptionController) HandleCreateOption(ctx *gin.Context) {
+	var req dto.CreateRegistrationOptionRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	option, err := u.registrationOptionSvc.CreateOption(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, option)
+}
+
+// @Summary Change the Price of a Registration Option
+// @Description Creates the next version of the option; the current version is deactivated but kept.
+// @Id updateRegistrationOption
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param optionID path string true "optionID"
+// @Param body body dto.UpdateRegistrationOptionRequest true "body"
+// @Success 200 {object} model.RegistrationOption
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-options/{optionID} [put]
+func (u *RegistrationOptionController) HandleUpdateOption(ctx *gin.Context) {
+	var req dto.UpdateRegistrationOptionRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	option, err := u.registrationOptionSvc.UpdateOption(ctx.Param("optionID"), req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, option)
+}
+
+// @Summary Put a Registration Option on Sale
+// @Id activateRegistrationOption
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param optionID path string true "optionID"
+// @Success 200 {object} model.RegistrationOption
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-options/{optionID}/activate [post]
+func (u *RegistrationOptionController) HandleActivateOption(ctx *gin.Context) {
+	u.setActive(ctx, true)
+}
+
+// @Summary Take a Registration Option off Sale
+// @Id deactivateRegistrationOption
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param optionID path string true "optionID"
+// @Success 200 {object} model.RegistrationOption
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-options/{optionID}/deactivate [post]
+func (u *RegistrationOptionController) HandleDeactivateOption(ctx *gin.Context) {
+	u.setActive(ctx, false)
+}
+
+func (u *RegistrationOptionController) setActive(ctx *gin.Context, active bool) {
+	option, err := u.registrationOptionSvc.SetOptionActive(ctx.Param("optionID"), active)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, option)
+}
+
+// @Summary List the Capacities of the Registration Categories
+// @Id listCategoryCapacities
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.CategoryCapacity
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-capacities [get]
+func (u *RegistrationOptionController) HandleListCapacities(ctx *gin.Context) {
+	capacities, err := u.registrationOptionSvc.ListCapacities()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, capacities)
+}
+
+// @Summary Replace the Capacities of the Registration Categories
+// @Description Categories left out are unlimited. A capacity counts the paid registrations and the unpaid ones holding a seat.
+// @Id replaceCategoryCapacities
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param body body dto.ReplaceCategoryCapacitiesRequest true "body"
+// @Success 200 {array} model.CategoryCapacity
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/registration-capacities [put]
+func (u *RegistrationOptionController) HandleReplaceCapacities(ctx *gin.Context) {
+	var req dto.ReplaceCategoryCapacitiesRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	capacities, err := u.registrationOptionSvc.ReplaceCapacities(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, capacities)
+}
+
+func NewRegistrationOptionController(registrationOptionSvc service.RegistrationOptionService) *RegistrationOptionController {
+	return &RegistrationOptionController{
+		registrationOptionSvc: registrationOptionSvc,
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/middleware"
 	"ashno-onepay/internal/trace"
 	"github.com/gin-gonic/gin"
 	"log"
@@ -23,3 +24,11 @@ func handleError(ctx *gin.Context, err error) {
 		ctx.JSON(errors.ErrInternal.StatusCode, err)
 	}
 }
+
+func currentUserID(ctx *gin.Context) string {
+	claims, ok := middleware.GetCurrentUserClaims(ctx)
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	"ashno-onepay/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Join the Waitlist of a Sold-out Category
+// @Description Takes the same body as a registration. When a seat frees up the registration is made and the attendee is emailed a link to pay for it before the invitation expires.
+// @Id joinWaitlist
+// @Tags register
+// @version 1.0
+// @Param body body dto.RegistrationRequest true "body"
+// @Success 200 {object} model.WaitlistEntry
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/waitlist [post]
+func (u *RegistrationController) HandleJoinWaitlist(ctx *gin.Context) {
+	var req dto.RegistrationRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		handleError(ctx, errors.ErrBadRequest.Wrap(err).Reform("json marshal failed"))
+		return
+	}
+	entry, err := u.registrationSvc.JoinWaitlist(req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// @Summary Get a Waitlist Entry and Its Position in the Queue
+// @Id getWaitlistEntry
+// @Tags register
+// @version 1.0
+// @Param entryID path string true "entryID"
+// @Success 200 {object} model.WaitlistEntry
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /register/waitlist/{entryID} [get]
+func (u *RegistrationController) HandleGetWaitlistEntry(ctx *gin.Context) {
+	entry, err := u.registrationSvc.GetWaitlistEntry(ctx.Param("entryID"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// @Summary List the Waitlist
+// @Id listWaitlist
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Param category query string false "registration option category, every category when empty"
+// @Param status query string false "waiting, invited, registered or expired; every entry when empty"
+// @Success 200 {array} model.WaitlistEntry
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/waitlist [get]
+func (u *RegistrationController) HandleListWaitlist(ctx *gin.Context) {
+	entries, err := u.registrationSvc.ListWaitlist(ctx.Query("category"), ctx.Query("status"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// @Summary Offer the Free Seats to the Waitlist Now
+// @Description Seats are offered periodically; this runs the offer at once, e.g. after raising a capacity.
+// @Id inviteFromWaitlist
+// @Tags admin
+// @version 1.0
+// @Security SessionKey
+// @Success 200 {array} model.WaitlistEntry
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/waitlist/invitations [post]
+func (u *RegistrationController) HandleInviteFromWaitlist(ctx *gin.Context) {
+	if err := u.registrationSvc.InviteFromWaitlist(); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	entries, err := u.registrationSvc.ListWaitlist("", "invited")
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
@@ -13,6 +13,7 @@ var (
 
 	ErrUnauthorized = NewAppError(400108, http.StatusBadRequest, "unauthorized request")
 	ErrInvalidValue = NewAppError(400111, http.StatusBadRequest, "invalid value")
+	ErrPromoCode    = NewAppError(400112, http.StatusBadRequest, "promo code not accepted")
 	ErrInternal     = NewAppError(500901, http.StatusInternalServerError, "internal error")
 
 	ErrInvalidSession     = NewAppError(401, http.StatusUnauthorized, "invalid session")
@@ -20,6 +21,9 @@ var (
 	ErrInvalidPassword    = NewAppError(401, http.StatusUnauthorized, "invalid password")
 	ErrForbidden          = NewAppError(403, http.StatusUnauthorized, "forbidden")
 	ErrNotFound           = NewAppError(404, http.StatusNotFound, "not found")
+	ErrPaymentExpired     = NewAppError(410, http.StatusGone, "payment link expired")
+	ErrRegistrationClosed = NewAppError(409, http.StatusConflict, "registration closed")
+	ErrSoldOut            = NewAppError(409001, http.StatusConflict, "sold out")
 	ErrOtherService       = NewAppError(7500001, http.StatusInternalServerError, "other service error")
 	ErrDatabase           = NewAppError(7050004, http.StatusInternalServerError, "Server error")
 	ErrHttpRequestTimeout = NewAppError(7500005, http.StatusRequestTimeout, "http request timeout")
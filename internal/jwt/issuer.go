@@ -8,6 +8,8 @@ import (
 
 type Issuer interface {
 	Issue(ctx context.Context, userClaim *UserClaims) (string, error)
+	IssuePaymentResult(ctx context.Context, claims *PaymentResultClaims) (string, error)
+	IssueManage(ctx context.Context, claims *ManageClaims) (string, error)
 }
 
 type issuer struct {
@@ -21,6 +23,18 @@ func NewIssuer(jwtSecret string) Issuer {
 }
 
 func (i *issuer) Issue(ctx context.Context, userClaim *UserClaims) (string, error) {
+	userClaim.Audience = SessionAudience
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaim)
 	return token.SignedString([]byte(i.jwtSecret))
 }
+
+func (i *issuer) IssuePaymentResult(ctx context.Context, claims *PaymentResultClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(i.jwtSecret))
+}
+
+func (i *issuer) IssueManage(ctx context.Context, claims *ManageClaims) (string, error) {
+	claims.Audience = ManageAudience
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(i.jwtSecret))
+}
@@ -0,0 +1,16 @@
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt"
+)
+
+// ManageAudience tells manage tokens apart from the other tokens signed with the same key.
+const ManageAudience = "manage_registration"
+
+// ManageClaims lets an attendee edit their registration from a magic link. Subject is the
+// registration ID; Email is the registration's when the link was sent, so a link stops working
+// once the registration is handed over to someone else.
+type ManageClaims struct {
+	jwt.StandardClaims
+	Email string `json:"email"`
+}
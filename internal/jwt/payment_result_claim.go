@@ -0,0 +1,17 @@
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt"
+)
+
+// PaymentResultClaims is the verified OnePay result handed to the result pages,
+// so they can show it without trusting the query string. Subject is the registration ID,
+// empty for the payment of a sponsor group, which GroupID is set for.
+type PaymentResultClaims struct {
+	jwt.StandardClaims
+	GroupID      string `json:"group_id,omitempty"`
+	Result       string `json:"result"`
+	ResponseCode string `json:"response_code"`
+	MerchTxnRef  string `json:"merch_txn_ref"`
+	OrderInfo    string `json:"order_info"`
+}
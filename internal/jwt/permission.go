@@ -0,0 +1,70 @@
+package jwt
+
+// Permission is something a role allows on the admin routes, named resource:action.
+// payments:manage covers refunds and confirming bank transfer and cash payments.
+type Permission string
+
+const (
+	PermissionRegistrationsRead    Permission = "registrations:read"
+	PermissionRegistrationsEdit    Permission = "registrations:edit"
+	PermissionRegistrationsExport  Permission = "registrations:export"
+	PermissionRegistrationsCheckIn Permission = "registrations:check_in"
+	PermissionPaymentsRead         Permission = "payments:read"
+	PermissionPaymentsManage       Permission = "payments:manage"
+	PermissionSettingsRead         Permission = "settings:read"
+	PermissionSettingsManage       Permission = "settings:manage"
+	PermissionUsersManage          Permission = "users:manage"
+)
+
+// AllPermissions lists every permission, the ones AdminRole has.
+var AllPermissions = []Permission{
+	PermissionRegistrationsRead,
+	PermissionRegistrationsEdit,
+	PermissionRegistrationsExport,
+	PermissionRegistrationsCheckIn,
+	PermissionPaymentsRead,
+	PermissionPaymentsManage,
+	PermissionSettingsRead,
+	PermissionSettingsManage,
+	PermissionUsersManage,
+}
+
+// rolePermissions is what each role that can be given to an admin user allows.
+var rolePermissions = map[Role][]Permission{
+	AdminRole: AllPermissions,
+	StaffRole: {
+		PermissionRegistrationsRead,
+		PermissionRegistrationsExport,
+		PermissionRegistrationsCheckIn,
+		PermissionSettingsRead,
+	},
+	FinanceRole: {
+		PermissionRegistrationsRead,
+		PermissionPaymentsRead,
+		PermissionPaymentsManage,
+		PermissionSettingsRead,
+	},
+	CheckInRole: {
+		PermissionRegistrationsCheckIn,
+	},
+}
+
+// Roles lists the roles that can be given to an admin user.
+func Roles() []Role {
+	return []Role{AdminRole, StaffRole, FinanceRole, CheckInRole}
+}
+
+// Permissions returns what r allows.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// Can reports whether r allows permission.
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
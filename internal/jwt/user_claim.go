@@ -4,21 +4,32 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+// SessionAudience tells admin sessions apart from the other tokens signed with the same key.
+const SessionAudience = "session"
+
+// UserClaims is the session of an admin user. Subject is the user ID and Id a unique token ID.
 type UserClaims struct {
 	jwt.StandardClaims
-	Role Role `json:"role"`
+	Email string `json:"email"`
+	Role  Role   `json:"role"`
 }
 
 type Role string
 
 const (
+	// AdminRole is for the organisers and can do everything
 	AdminRole Role = "admin"
-	UserRole  Role = "user"
+	// StaffRole may look registrations up, export them and check attendees in but not change anything
+	StaffRole Role = "staff"
+	// FinanceRole sees payments and refunds them but cannot edit attendees
+	FinanceRole Role = "finance"
+	// CheckInRole is for the volunteers scanning tickets at the venue
+	CheckInRole Role = "check_in"
+	UserRole    Role = "user"
 )
 
-//func NewUserClaims(user model.User, role Role) *UserClaims {
-//	return &UserClaims{
-//		Role:           role,
-//		StandardClaims: jwt.StandardClaims{Id: strconv.Itoa(int(user.Id))},
-//	}
-//}
+// IsValid reports whether r can be given to an admin user.
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
@@ -10,6 +10,8 @@ import (
 
 type Validator interface {
 	Validate(ctx context.Context, token string) (*UserClaims, error)
+	ValidatePaymentResult(ctx context.Context, token string) (*PaymentResultClaims, error)
+	ValidateManage(ctx context.Context, token string) (*ManageClaims, error)
 }
 
 type validatorImpl struct {
@@ -47,5 +49,35 @@ func (v *validatorImpl) Validate(ctx context.Context, jwtToken string) (*UserCla
 	if claims.ExpiresAt < time.Now().Unix() {
 		return claims, errors.ErrInvalidSession.Reform(ErrorExpiredToken.Error())
 	}
+	// payment result and manage tokens are signed with the same key but are not sessions
+	if !claims.VerifyAudience(SessionAudience, true) || claims.Subject == "" || claims.Id == "" {
+		return nil, errors.ErrInvalidSession.Reform(ErrInvalidToken.Error())
+	}
 	return claims, err
 }
+
+func (v *validatorImpl) ValidatePaymentResult(ctx context.Context, jwtToken string) (*PaymentResultClaims, error) {
+	claims := new(PaymentResultClaims)
+	_, err := jwt.ParseWithClaims(jwtToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(v.jwtSecret), nil
+	})
+	if err != nil {
+		return nil, errors.ErrInvalidSession.Wrap(err).Reform(ErrInvalidToken.Error())
+	}
+	return claims, nil
+}
+
+// ValidateManage parses a magic link token, rejecting expired ones and tokens issued for anything else.
+func (v *validatorImpl) ValidateManage(ctx context.Context, jwtToken string) (*ManageClaims, error) {
+	claims := new(ManageClaims)
+	_, err := jwt.ParseWithClaims(jwtToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(v.jwtSecret), nil
+	})
+	if err != nil {
+		return nil, errors.ErrInvalidSession.Wrap(err).Reform("link is invalid or has expired, request a new one")
+	}
+	if !claims.VerifyAudience(ManageAudience, true) || claims.Subject == "" || claims.ExpiresAt == 0 {
+		return nil, errors.ErrInvalidSession.Reform(ErrInvalidToken.Error())
+	}
+	return claims, nil
+}
@@ -11,7 +11,12 @@ const (
 	SessionKeyHeader        = "session-key"
 )
 
-func NewSessionMiddleware(validator jwt.Validator) func(c *gin.Context) {
+// RevocationList tells the access tokens ended before they expire, by StandardClaims.Id.
+type RevocationList interface {
+	IsRevoked(tokenID string) (bool, error)
+}
+
+func NewSessionMiddleware(validator jwt.Validator, revocations RevocationList) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		token := c.GetHeader(SessionKeyHeader)
 		claims, err := validator.Validate(c.Request.Context(), token)
@@ -19,12 +24,47 @@ func NewSessionMiddleware(validator jwt.Validator) func(c *gin.Context) {
 			handleAuthError(c, err)
 			return
 		}
+		revoked, err := revocations.IsRevoked(claims.Id)
+		if err != nil {
+			handleError(c, err, errors.ErrInternal)
+			return
+		}
+		if revoked {
+			handleAuthError(c, errors.ErrInvalidSession.Reform("session has ended, sign in again"))
+			return
+		}
 
 		c.Set(CtxKeyCurrentUserClaims, claims)
 		c.Next()
 	}
 }
 
+// RequirePermission only lets through sessions whose role has permission, see jwt.Role.Permissions.
+// It must run after the session middleware.
+func RequirePermission(permission jwt.Permission) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		claims, ok := GetCurrentUserClaims(c)
+		if !ok {
+			handleAuthError(c, errors.ErrInvalidSession)
+			return
+		}
+		if !claims.Role.Can(permission) {
+			handleError(c, errors.ErrForbidden.Reform("%s is not allowed to %s", claims.Role, permission), errors.ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+func GetCurrentUserClaims(c *gin.Context) (*jwt.UserClaims, bool) {
+	value, ok := c.Get(CtxKeyCurrentUserClaims)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.UserClaims)
+	return claims, ok
+}
+
 func handleAuthError(ctx *gin.Context, err error) {
 	handleError(ctx, err, errors.ErrUnauthorized)
 }
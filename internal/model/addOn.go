@@ -0,0 +1,101 @@
+package model
+
+// AddOn is a product sold on top of a registration, e.g. the gala dinner or a workshop.
+type AddOn struct {
+	BaseModel
+
+	Code        string `gorm:"type:varchar(50);not null;uniqueIndex" json:"code"`
+	Name        string `gorm:"type:varchar(255);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	Capacity    int    `gorm:"not null;default:0" json:"capacity"` // 0 is unlimited
+	Active      bool   `gorm:"default:true" json:"active"`
+
+	Prices []AddOnPrice `gorm:"foreignKey:AddOnID;constraint:OnDelete:CASCADE" json:"prices"`
+}
+
+// PriceFor returns the price of the add-on during the period subtype, nil when it has none.
+func (a AddOn) PriceFor(subtype string) *AddOnPrice {
+	var fallback *AddOnPrice
+	for i := range a.Prices {
+		switch a.Prices[i].Subtype {
+		case subtype:
+			return &a.Prices[i]
+		case "":
+			fallback = &a.Prices[i]
+		}
+	}
+	return fallback
+}
+
+// AddOnPrice is the price of an add-on during a registration period.
+// The price with an empty Subtype applies to the periods without their own.
+type AddOnPrice struct {
+	BaseModel
+
+	AddOnID string  `gorm:"type:varchar(100);not null;uniqueIndex:idx_add_on_price_period" json:"add_on_id"`
+	Subtype string  `gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_add_on_price_period" json:"subtype"`
+	FeeUSD  float64 `gorm:"not null" json:"fee_usd"`
+	FeeVND  int64   `gorm:"not null" json:"fee_vnd"`
+}
+
+// RegistrationLineItem is an add-on bought with a registration, priced when it was ordered.
+type RegistrationLineItem struct {
+	BaseModel
+
+	RegistrationID string `gorm:"type:varchar(100);not null;index" json:"registration_id"`
+	AddOnID        string `gorm:"type:varchar(100);not null;index" json:"add_on_id"`
+	Code           string `gorm:"type:varchar(50)" json:"code"`
+	Name           string `gorm:"type:varchar(255)" json:"name"`
+	// TransactionID is the accompany person purchase the item was ordered with, empty for the registration itself
+	TransactionID string `gorm:"type:varchar(100);index" json:"transaction_id"`
+	// AccompanyPersons is true when the item was bought for the accompanying persons
+	AccompanyPersons bool    `json:"accompany_persons"`
+	Quantity         int     `gorm:"not null" json:"quantity"`
+	UnitFeeUSD       float64 `gorm:"not null" json:"unit_fee_usd"`
+	UnitFeeVND       int64   `gorm:"not null" json:"unit_fee_vnd"`
+	Status           string  `gorm:"type:varchar(50);default:'pending'" json:"status"`
+}
+
+type LineItemStatus string
+
+const (
+	LineItemStatusPending LineItemStatus = "pending"
+	LineItemStatusDone    LineItemStatus = "done"
+	LineItemStatusFail    LineItemStatus = "fail"
+	// LineItemStatusCancelled is an item of a cancelled registration, its capacity is free again
+	LineItemStatusCancelled LineItemStatus = "cancelled"
+)
+
+const AddOnCodeGalaDinner = "gala_dinner"
+
+// LineItemList is the add-ons of a registration.
+type LineItemList []RegistrationLineItem
+
+// IsVoid reports whether the item failed to be paid or was cancelled.
+func (i RegistrationLineItem) IsVoid() bool {
+	return i.Status == string(LineItemStatusFail) || i.Status == string(LineItemStatusCancelled)
+}
+
+// Total returns the price of the items not void, in USD and VND.
+func (l LineItemList) Total() (float64, int64) {
+	var feeUSD float64
+	var feeVND int64
+	for _, item := range l {
+		if item.IsVoid() {
+			continue
+		}
+		feeUSD += float64(item.Quantity) * item.UnitFeeUSD
+		feeVND += int64(item.Quantity) * item.UnitFeeVND
+	}
+	return feeUSD, feeVND
+}
+
+// Has reports whether the registrant, not an accompanying person, holds an add-on.
+func (l LineItemList) Has(code string) bool {
+	for _, item := range l {
+		if item.Code == code && !item.AccompanyPersons && !item.IsVoid() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// AdminUser is someone signing in to the admin routes. Role is one of the jwt roles and goes
+// into the session issued on login.
+type AdminUser struct {
+	BaseModel
+
+	Email        string     `gorm:"type:varchar(100);not null;uniqueIndex" json:"email"` // stored lower-case
+	Name         string     `gorm:"type:varchar(100)" json:"name"`
+	PasswordHash string     `gorm:"type:varchar(100);not null" json:"-"`
+	Role         string     `gorm:"type:varchar(20);not null" json:"role"`
+	Active       bool       `gorm:"default:true" json:"active"`
+	LastLoginAt  *time.Time `gorm:"type:timestamp" json:"last_login_at"`
+}
+
+// NormalizeEmail is how admin emails are stored and looked up.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,25 @@
+package model
+
+// AuditLog records who changed a registration outside the payment flow, and how.
+type AuditLog struct {
+	BaseModel
+
+	RegistrationID string      `gorm:"type:varchar(100);not null;index" json:"registration_id"`
+	Action         AuditAction `gorm:"type:varchar(50);not null" json:"action"`
+	// Actor is the admin user ID, or the attendee's email for their own requests
+	Actor  string `gorm:"type:varchar(100)" json:"actor"`
+	Detail string `gorm:"type:text" json:"detail"`
+}
+
+type AuditAction string
+
+const (
+	AuditActionCancellationRequested AuditAction = "cancellation_requested"
+	AuditActionSubstitutionRequested AuditAction = "substitution_requested"
+	AuditActionChangeApproved        AuditAction = "change_approved"
+	AuditActionChangeRejected        AuditAction = "change_rejected"
+	AuditActionCancelled             AuditAction = "cancelled"
+	AuditActionSubstituted           AuditAction = "substituted"
+	AuditActionDetailsEdited         AuditAction = "details_edited"
+	AuditActionCheckedIn             AuditAction = "checked_in"
+)
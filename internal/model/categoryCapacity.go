@@ -0,0 +1,24 @@
+package model
+
+// CategoryCapacity limits the seats sold for a RegistrationOption category, whatever the period.
+// A registration holds a seat once paid, or while pending until its ReservedUntil; a failed
+// payment frees it.
+type CategoryCapacity struct {
+	BaseModel
+
+	Category string `gorm:"type:varchar(100);not null;uniqueIndex" json:"category"`
+	Capacity int    `gorm:"not null;default:0" json:"capacity"` // 0 is unlimited
+
+	// Reserved is the number of seats paid or held, Waiting the number of attendees on the waitlist
+	Reserved int `gorm:"-" json:"reserved"`
+	Waiting  int `gorm:"-" json:"waiting"`
+}
+
+// Remaining is the number of seats left for new registrations, -1 when unlimited. The seats freed
+// while attendees are waiting are theirs.
+func (c CategoryCapacity) Remaining() int {
+	if c.Capacity == 0 {
+		return -1
+	}
+	return max(c.Capacity-c.Reserved-c.Waiting, 0)
+}
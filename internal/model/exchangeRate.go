@@ -0,0 +1,11 @@
+package model
+
+// ExchangeRate is a rate set by an admin that overrides the rate provider until it is removed.
+type ExchangeRate struct {
+	BaseModel
+
+	Base      string  `gorm:"type:varchar(10);not null;uniqueIndex:idx_exchange_rate_pair" json:"base"`
+	Quote     string  `gorm:"type:varchar(10);not null;uniqueIndex:idx_exchange_rate_pair" json:"quote"`
+	Rate      float64 `gorm:"not null" json:"rate"`
+	UpdatedBy string  `gorm:"type:varchar(100)" json:"updated_by"`
+}
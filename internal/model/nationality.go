@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 type Country struct {
 	Code string `json:"code"`
 	Name string `json:"name"`
@@ -14,6 +16,16 @@ func GetCountryName(code string) string {
 	return ""
 }
 
+// GetCountryCode returns the code of a country given by code or by name, empty when unknown.
+func GetCountryCode(codeOrName string) string {
+	for _, country := range Countries {
+		if strings.EqualFold(country.Code, codeOrName) || strings.EqualFold(country.Name, codeOrName) {
+			return country.Code
+		}
+	}
+	return ""
+}
+
 var Countries = []Country{
 	{Code: "af", Name: "Afghanistan"},
 	{Code: "ax", Name: "Aland Islands"},
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// PaymentTransaction is one OnePay payment attempt. A row is written every time a
+// payment URL is generated and updated with the result reported by OnePay.
+type PaymentTransaction struct {
+	BaseModel
+
+	RegistrationID string     `gorm:"type:varchar(100);index" json:"registration_id"`
+	GroupID        string     `gorm:"type:varchar(100);index" json:"group_id"` // set instead of RegistrationID for a sponsor group payment
+	MerchTxnRef    string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_payment_transaction_ref" json:"merch_txn_ref"`
+	OrderInfo      string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_payment_transaction_ref" json:"order_info"`
+	OrderType      string     `gorm:"type:varchar(20)" json:"order_type"`
+	PaymentMethod  string     `gorm:"type:varchar(50)" json:"payment_method"`
+	Amount         int64      `gorm:"not null" json:"amount"` // e.g., 1800000 = 1,800,000 VND, not multiplied by 100
+	Currency       string     `gorm:"type:varchar(10)" json:"currency"`
+	ExchangeRate   float64    `json:"exchange_rate"` // USD to VND rate Amount was converted with, 0 if not converted
+	ClientIP       string     `gorm:"type:varchar(100)" json:"client_ip"`
+	ExpiresAt      *time.Time `gorm:"type:timestamp" json:"expires_at"`
+
+	Status       string     `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	ResponseCode string     `gorm:"type:varchar(10)" json:"response_code"`
+	Message      string     `gorm:"type:varchar(255)" json:"message"`
+	RawQuery     string     `gorm:"type:text" json:"raw_query"`
+	CompletedAt  *time.Time `gorm:"type:timestamp" json:"completed_at"`
+}
+
+// IsExpired reports whether the attempt's link had expired at at.
+func (t PaymentTransaction) IsExpired(at time.Time) bool {
+	return t.ExpiresAt != nil && at.After(*t.ExpiresAt)
+}
+
+// PaymentTransactionResult is the outcome of an attempt as reported by OnePay.
+type PaymentTransactionResult struct {
+	Status       string
+	ResponseCode string
+	Message      string
+	RawQuery     string
+}
+
+type PaymentTransactionStatus string
+
+const (
+	PaymentTransactionStatusPending PaymentTransactionStatus = "pending"
+	PaymentTransactionStatusSuccess PaymentTransactionStatus = "success"
+	PaymentTransactionStatusFail    PaymentTransactionStatus = "fail"
+	// PaymentTransactionStatusAbandoned marks an attempt OnePay never received, e.g. the payer closed the page.
+	PaymentTransactionStatusAbandoned PaymentTransactionStatus = "abandoned"
+	// PaymentTransactionStatusExpired marks an attempt whose result arrived after its link expired.
+	PaymentTransactionStatusExpired PaymentTransactionStatus = "expired"
+)
+
+type OrderType string
+
+const (
+	OrderTypeRegistration    OrderType = "ORDER"
+	OrderTypeAccompanyPerson OrderType = "ACCOM"
+	OrderTypeGroup           OrderType = "GROUP"
+)
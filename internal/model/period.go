@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Period is a registration period such as early-bird. The registration options whose
+// Subtype equals Name are the ones sold while the period is active.
+// A period is active from StartAt (inclusive) to EndAt (exclusive).
+type Period struct {
+	BaseModel
+
+	Name     string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	StartAt  time.Time `gorm:"type:timestamp;not null" json:"start_at"`
+	EndAt    time.Time `gorm:"type:timestamp;not null" json:"end_at"`
+	TimeZone string    `gorm:"type:varchar(100);not null" json:"time_zone"` // the time zone the period was entered in
+}
+
+func (Period) TableName() string {
+	return "registration_periods"
+}
+
+func (p Period) IsActive(at time.Time) bool {
+	return !at.Before(p.StartAt) && at.Before(p.EndAt)
+}
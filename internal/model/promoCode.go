@@ -0,0 +1,113 @@
+package model
+
+import (
+	"ashno-onepay/internal/errors"
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+)
+
+// PromoCode reduces or waives the registration option fee, e.g. for sponsors, speakers or faculty.
+// Add-ons are charged in full.
+type PromoCode struct {
+	BaseModel
+
+	Code        string       `gorm:"type:varchar(50);not null;uniqueIndex" json:"code"` // stored upper-case
+	Description string       `gorm:"type:text" json:"description"`
+	Type        DiscountType `gorm:"type:varchar(20);not null" json:"type"`
+	Percent     float64      `json:"percent"`                            // for DiscountTypePercentage, e.g. 20 = 20%
+	AmountUSD   float64      `json:"amount_usd"`                         // for DiscountTypeFixed
+	AmountVND   int64        `json:"amount_vnd"`                         // for DiscountTypeFixed
+	MaxUses     int          `gorm:"not null;default:0" json:"max_uses"` // 0 is unlimited
+	ValidFrom   *time.Time   `gorm:"type:timestamp" json:"valid_from"`
+	ValidUntil  *time.Time   `gorm:"type:timestamp" json:"valid_until"`
+	// Categories the code is accepted for, every category when empty
+	Categories StringList `gorm:"type:jsonb" json:"categories"`
+	// Email binds the code to one registrant, anyone may use it when empty
+	Email  string `gorm:"type:varchar(100)" json:"email"`
+	Active bool   `json:"active"` // no gorm default, which would store false as true
+
+	// Used is the number of paid registrations made with the code
+	Used int64 `gorm:"-" json:"used"`
+}
+
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+	DiscountTypeWaiver     DiscountType = "waiver"
+)
+
+// NormalizePromoCode is the form codes are stored and looked up in.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValidAt reports whether the code is active and inside its validity window at t.
+func (p PromoCode) IsValidAt(at time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.ValidFrom != nil && at.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !at.Before(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// AcceptsCategory reports whether the code can be used for a registration category.
+func (p PromoCode) AcceptsCategory(category string) bool {
+	if len(p.Categories) == 0 {
+		return true
+	}
+	for _, c := range p.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsEmail reports whether the code can be used by the registrant with this email.
+func (p PromoCode) AcceptsEmail(email string) bool {
+	return p.Email == "" || strings.EqualFold(p.Email, strings.TrimSpace(email))
+}
+
+// Discount returns how much the code takes off a fee, never more than the fee itself.
+func (p PromoCode) Discount(feeUSD float64, feeVND int64) (float64, int64) {
+	var discountUSD float64
+	var discountVND int64
+	switch p.Type {
+	case DiscountTypeWaiver:
+		discountUSD, discountVND = feeUSD, feeVND
+	case DiscountTypePercentage:
+		// whole dollars, as USD fees are charged
+		discountUSD = math.Round(feeUSD * p.Percent / 100)
+		discountVND = int64(math.Round(float64(feeVND) * p.Percent / 100))
+	case DiscountTypeFixed:
+		discountUSD, discountVND = p.AmountUSD, p.AmountVND
+	}
+	return min(discountUSD, feeUSD), min(discountVND, feeVND)
+}
+
+type StringList []string
+
+func (s *StringList) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(s))
+}
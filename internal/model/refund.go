@@ -0,0 +1,28 @@
+package model
+
+// Refund is a request to OnePay to give back (part of) a successful payment transaction.
+type Refund struct {
+	BaseModel
+
+	RegistrationID       string `gorm:"type:varchar(100);index" json:"registration_id"`
+	PaymentTransactionID string `gorm:"type:varchar(100);index" json:"payment_transaction_id"`
+	MerchTxnRef          string `gorm:"type:varchar(100);not null;uniqueIndex" json:"merch_txn_ref"`
+	OrgMerchTxnRef       string `gorm:"type:varchar(100);not null" json:"org_merch_txn_ref"`
+	Amount               int64  `gorm:"not null" json:"amount"` // same unit as PaymentTransaction.Amount
+	Currency             string `gorm:"type:varchar(10)" json:"currency"`
+	Reason               string `gorm:"type:varchar(255)" json:"reason"`
+	RequestedBy          string `gorm:"type:varchar(100)" json:"requested_by"`
+
+	Status       string `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	ResponseCode string `gorm:"type:varchar(10)" json:"response_code"`
+	Message      string `gorm:"type:varchar(255)" json:"message"`
+	RawQuery     string `gorm:"type:text" json:"raw_query"`
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending RefundStatus = "pending"
+	RefundStatusSuccess RefundStatus = "success"
+	RefundStatusFail    RefundStatus = "fail"
+)
@@ -4,6 +4,7 @@ import (
 	"ashno-onepay/internal/errors"
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 )
 
 type Registration struct {
@@ -24,8 +25,60 @@ type Registration struct {
 	PhoneNumber          string `gorm:"type:varchar(20)" json:"phone_number"`
 	Sponsor              string `gorm:"type:varchar(255)" json:"sponsor"`
 
+	PaymentMethod    string              `gorm:"type:varchar(50)" json:"payment_method"`
 	PaymentStatus    string              `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
 	AccompanyPersons AccompanyPersonList `gorm:"type:jsonb" json:"accompany_persons"`
+	LineItems        LineItemList        `gorm:"foreignKey:RegistrationID;constraint:OnDelete:CASCADE" json:"line_items"`
+
+	// TransferReference is the memo a bank transfer for the registration must carry
+	TransferReference string `gorm:"type:varchar(30);uniqueIndex:idx_registrations_transfer_reference,where:transfer_reference <> ''" json:"transfer_reference"`
+
+	// TicketCode is set when the ticket is reissued, e.g. to a substitute attendee; the QR codes
+	// sent before then no longer match the registration
+	TicketCode string `gorm:"type:varchar(50)" json:"-"`
+
+	// CheckedInAt is when the attendee's ticket was scanned at the venue, CheckedInBy the admin user who scanned it
+	CheckedInAt *time.Time `gorm:"type:timestamp" json:"checked_in_at"`
+	CheckedInBy string     `gorm:"type:varchar(100)" json:"checked_in_by"`
+
+	// ReservedUntil is when an unpaid registration stops holding its seat, see CategoryCapacity
+	ReservedUntil *time.Time `gorm:"type:timestamp" json:"reserved_until"`
+
+	// GroupID is the sponsor group that registered and pays for the registration
+	GroupID string `gorm:"type:varchar(100);index" json:"group_id"`
+
+	PromoCodeID string  `gorm:"type:varchar(100);index" json:"promo_code_id"`
+	PromoCode   string  `gorm:"type:varchar(50)" json:"promo_code"`
+	DiscountUSD float64 `json:"discount_usd"` // taken off the registration option fee by the promo code
+	DiscountVND int64   `json:"discount_vnd"`
+}
+
+// Fee is the registration option price less the promo code discount plus the add-ons, in USD and VND.
+func (r Registration) Fee() (float64, int64) {
+	feeUSD, feeVND := r.LineItems.Total()
+	optionUSD := max(r.RegistrationOption.FeeUSD-r.DiscountUSD, 0)
+	optionVND := max(r.RegistrationOption.FeeVND-r.DiscountVND, 0)
+	return optionUSD + feeUSD, optionVND + feeVND
+}
+
+// TicketPath is what the ticket QR code points to under OnePay.ReturnURL.
+func (r Registration) TicketPath() string {
+	if r.TicketCode == "" {
+		return r.Id
+	}
+	return r.Id + "?ticket=" + r.TicketCode
+}
+
+// HasTicket reports whether ticketCode, as read from a QR code, belongs to the current ticket.
+func (r Registration) HasTicket(ticketCode string) bool {
+	return r.TicketCode == "" || r.TicketCode == ticketCode
+}
+
+// AttendsGalaDinner reports whether the registrant bought the gala dinner, either as an add-on
+// or, for registrations made before add-ons existed, through a "+ Gala Dinner" option.
+func (r Registration) AttendsGalaDinner() bool {
+	category := RegistrationCategory(r.RegistrationOption.Category)
+	return r.LineItems.Has(AddOnCodeGalaDinner) || category == DoctorAndDinnerCategory || category == StudentAndDinnerCategory
 }
 
 type AccompanyPerson struct {
@@ -65,10 +118,43 @@ const (
 	PaymentStatusPending PaymentStatus = "pending"
 	PaymentStatusFail    PaymentStatus = "fail"
 	PaymentStatusDone    PaymentStatus = "done"
+
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	// PaymentStatusCancelled is a paid registration cancelled by its attendee, refunded or not
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodOnePayDomestic      PaymentMethod = "onepay_domestic"
+	PaymentMethodOnePayInternational PaymentMethod = "onepay_international"
+	// PaymentMethodBankTransfer and PaymentMethodCash are paid outside OnePay; an admin confirms receipt
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodCash         PaymentMethod = "cash"
+	// PaymentMethodOffline is for registrations paid outside OnePay and recorded by an admin
+	PaymentMethodOffline PaymentMethod = "offline"
 )
 
+// IsOnePay reports whether payments made with the method go through the OnePay paygate.
+func (m PaymentMethod) IsOnePay() bool {
+	return m == PaymentMethodOnePayDomestic || m == PaymentMethodOnePayInternational
+}
+
+// DefaultPaymentMethod is used when the attendee did not choose one:
+// domestic ATM cards for Vietnamese attendees, international cards for everyone else.
+func DefaultPaymentMethod(nationality string) PaymentMethod {
+	if nationality == NationalityVietNam {
+		return PaymentMethodOnePayDomestic
+	}
+	return PaymentMethodOnePayInternational
+}
+
 type RegistrationCategory string
 
+// DoctorAndDinnerCategory and StudentAndDinnerCategory are no longer sold, the gala dinner
+// is an add-on. They remain for the registrations made with them.
 const (
 	DoctorCategory           RegistrationCategory = "ENT Doctors"
 	StudentCategory          RegistrationCategory = "Student & Trainees"
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"ashno-onepay/internal/errors"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// RegistrationChange is an attendee's request to cancel a paid registration or to hand it over
+// to someone else. Nothing changes until an admin approves it.
+type RegistrationChange struct {
+	BaseModel
+
+	RegistrationID string       `gorm:"type:varchar(100);not null;index" json:"registration_id"`
+	Type           ChangeType   `gorm:"type:varchar(20);not null" json:"type"`
+	Status         ChangeStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Reason         string       `gorm:"type:text" json:"reason"`
+
+	// Substitute is who takes the registration over, for substitutions only
+	Substitute Attendee `gorm:"type:jsonb" json:"substitute"`
+
+	// RefundPercent and RefundAmount are what the refund policy grants on the day of the request,
+	// RefundAmount in Currency; the admin may refund another amount when approving
+	RefundPercent float64 `json:"refund_percent"`
+	RefundAmount  int64   `json:"refund_amount"`
+	Currency      string  `gorm:"type:varchar(10)" json:"currency"`
+	RefundID      string  `gorm:"type:varchar(100)" json:"refund_id"` // the OnePay refund made on approval
+
+	DecidedBy    string     `gorm:"type:varchar(100)" json:"decided_by"`
+	DecidedAt    *time.Time `gorm:"type:timestamp" json:"decided_at"`
+	DecisionNote string     `gorm:"type:text" json:"decision_note"`
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeCancellation ChangeType = "cancellation"
+	ChangeTypeSubstitution ChangeType = "substitution"
+)
+
+type ChangeStatus string
+
+const (
+	ChangeStatusPending  ChangeStatus = "pending"
+	ChangeStatusApproved ChangeStatus = "approved"
+	ChangeStatusRejected ChangeStatus = "rejected"
+)
+
+// Attendee is the person a registration is for.
+type Attendee struct {
+	DoctorateDegree string `json:"doctorate_degree"`
+	FirstName       string `json:"first_name"`
+	MiddleName      string `json:"middle_name"`
+	LastName        string `json:"last_name"`
+	DateOfBirth     string `json:"date_of_birth"`
+	Institution     string `json:"institution"`
+	Email           string `json:"email"`
+	PhoneNumber     string `json:"phone_number"`
+}
+
+func (a *Attendee) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+func (a Attendee) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
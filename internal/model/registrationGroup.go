@@ -0,0 +1,34 @@
+package model
+
+// RegistrationGroup is a set of registrations a sponsor, e.g. a pharmaceutical company,
+// submits at once and pays for with a single payment.
+type RegistrationGroup struct {
+	BaseModel
+
+	Sponsor      string `gorm:"type:varchar(255);not null" json:"sponsor"`
+	ContactName  string `gorm:"type:varchar(255)" json:"contact_name"`
+	ContactEmail string `gorm:"type:varchar(100);not null;index" json:"contact_email"`
+	ContactPhone string `gorm:"type:varchar(20)" json:"contact_phone"`
+	// Nationality of the sponsor decides the currency it pays in, as for a registrant
+	Nationality   string `gorm:"type:varchar(100)" json:"nationality"`
+	PaymentMethod string `gorm:"type:varchar(50)" json:"payment_method"`
+	PaymentStatus string `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
+
+	// no foreign key constraint, group_id is empty for the registrations outside a group
+	Registrations []Registration `gorm:"foreignKey:GroupID;constraint:-" json:"registrations"`
+}
+
+// Fee is the sum of the fees of the registrations not paid yet, in USD and VND.
+func (g RegistrationGroup) Fee() (float64, int64) {
+	var feeUSD float64
+	var feeVND int64
+	for _, reg := range g.Registrations {
+		if reg.PaymentStatus == string(PaymentStatusDone) {
+			continue
+		}
+		regUSD, regVND := reg.Fee()
+		feeUSD += regUSD
+		feeVND += regVND
+	}
+	return feeUSD, feeVND
+}
@@ -1,5 +1,7 @@
 package model
 
+import "math"
+
 type RegistrationOption struct {
 	BaseModel
 	Category string `gorm:"type:varchar(100)" json:"category"`             // e.g., "Doctor"
@@ -9,13 +11,15 @@ type RegistrationOption struct {
 	FeeVND int64   `gorm:"not null" json:"fee_vnd"` // e.g., 12000000 = 12,000,000 VND
 
 	Active bool `gorm:"default:true" json:"active"`
+	// Version counts the price changes of a category and subtype. A price change creates a new
+	// version so registrations keep referencing the option they paid for.
+	Version int `gorm:"not null;default:1" json:"version"`
 }
 
-var GalaDinnerOnlyOption = RegistrationOption{
-	Category: "GalaDinnerOnly",
-	FeeUSD:   100,
-	FeeVND:   1000000,
-	Active:   true,
+// IsWholeUSD reports whether a USD amount is whole dollars. OnePay is paid in whole units of the
+// currency, see PaymentTransaction.Amount, so USD prices and discounts cannot have cents.
+func IsWholeUSD(amount float64) bool {
+	return amount == math.Trunc(amount)
 }
 
 type RegistrationPeriod string
@@ -32,4 +36,5 @@ type RegistrationOptionFilter struct {
 	AttendGalaDinner       bool
 	NumberAccompanyPersons int
 	Email                  string
+	PromoCode              string
 }
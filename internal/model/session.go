@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// RefreshToken renews the session of an admin user. Only a hash of the token is stored; each
+// token is used once and replaced by the one issued with the new access token.
+type RefreshToken struct {
+	BaseModel
+
+	UserID    string     `gorm:"type:varchar(100);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null" json:"expires_at"`
+	RevokedAt *time.Time `gorm:"type:timestamp" json:"revoked_at"`
+	// ReplacedBy is the refresh token issued when this one was used
+	ReplacedBy string `gorm:"type:varchar(100)" json:"replaced_by"`
+
+	// AccessTokenID is the jti of the access token issued with this refresh token, revoked with it
+	AccessTokenID        string    `gorm:"type:varchar(100);index" json:"access_token_id"`
+	AccessTokenExpiresAt time.Time `gorm:"type:timestamp" json:"access_token_expires_at"`
+}
+
+// RevokedToken is an access token rejected before it expires, e.g. on logout. Entries can be
+// dropped once ExpiresAt has passed.
+type RevokedToken struct {
+	BaseModel
+
+	TokenID   string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"token_id"`
+	ExpiresAt time.Time `gorm:"type:timestamp;not null;index" json:"expires_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// WaitlistEntry is an attendee waiting for a seat of a sold-out category. When a seat frees up the
+// registration they asked for is made and they are invited to pay for it before ExpiresAt.
+type WaitlistEntry struct {
+	BaseModel
+
+	Category  string         `gorm:"type:varchar(100);not null;index" json:"category"`
+	Email     string         `gorm:"type:varchar(100);not null;index" json:"email"`
+	FirstName string         `gorm:"type:varchar(100)" json:"first_name"`
+	LastName  string         `gorm:"type:varchar(100)" json:"last_name"`
+	Status    WaitlistStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	// Request is the registration request, as JSON, the registration is made from
+	Request string `gorm:"type:jsonb" json:"-"`
+
+	RegistrationID string     `gorm:"type:varchar(100)" json:"registration_id"`
+	InvitedAt      *time.Time `gorm:"type:timestamp" json:"invited_at"`
+	ExpiresAt      *time.Time `gorm:"type:timestamp" json:"expires_at"`
+
+	// Position is the place in the queue of a waiting entry, 1 being the next invited
+	Position int `gorm:"-" json:"position,omitempty"`
+}
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	WaitlistStatusInvited WaitlistStatus = "invited"
+	// WaitlistStatusRegistered is an invited attendee who paid for the seat
+	WaitlistStatusRegistered WaitlistStatus = "registered"
+	// WaitlistStatusExpired is an invited attendee who did not pay in time, or whose registration
+	// could no longer be made
+	WaitlistStatusExpired WaitlistStatus = "expired"
+)
@@ -0,0 +1,238 @@
+package onepay
+
+import (
+	"ashno-onepay/internal/config"
+	errs "ashno-onepay/internal/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PaymentRequest describes one payment attempt to redirect the payer to.
+type PaymentRequest struct {
+	MerchTxnRef string
+	OrderInfo   string
+	Amount      int64 // in the currency's main unit, e.g. 1800000 = 1,800,000 VND
+	Currency    string
+	Locale      string
+	ReturnURL   string
+	CallbackURL string
+	ClientIP    string
+	ExpiresAt   time.Time // zero when the attempt does not expire
+}
+
+// PaymentResult is a verified answer from OnePay about a payment, a refund or a QueryDR.
+type PaymentResult struct {
+	MerchTxnRef   string
+	OrderInfo     string
+	Amount        int64 // in the currency's main unit
+	ResponseCode  string
+	Message       string
+	TransactionNo string
+	Params        map[string]string
+	RawQuery      string
+}
+
+func (r PaymentResult) Succeeded() bool {
+	return r.ResponseCode == ResponseCodeSuccess
+}
+
+// QueryDRResult is the answer of a QueryDR. Exists is false when OnePay has never seen the transaction.
+type QueryDRResult struct {
+	PaymentResult
+	Exists bool
+}
+
+type RefundRequest struct {
+	MerchTxnRef    string // identifies the refund itself and must be unique
+	OrgMerchTxnRef string // the payment being refunded
+	Amount         int64  // in the currency's main unit
+	Operator       string
+}
+
+type Client struct {
+	config     config.OnePay
+	httpClient *http.Client
+}
+
+func NewClient(config config.OnePay) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) MerchantID() string {
+	return c.config.MerchantID
+}
+
+// Currency is the currency payments of this merchant profile are charged in.
+func (c *Client) Currency() string {
+	return c.config.Currency
+}
+
+// BuildPaymentURL returns the signed paygate URL the payer is redirected to.
+func (c *Client) BuildPaymentURL(req PaymentRequest) (string, error) {
+	merchantQueryMap := map[string]string{
+		"vpc_Version":     "2",
+		"vpc_Currency":    req.Currency,
+		"vpc_Command":     "pay",
+		"vpc_AccessCode":  c.config.AccessCode,
+		"vpc_Merchant":    c.config.MerchantID,
+		"vpc_Locale":      req.Locale,
+		"vpc_ReturnURL":   req.ReturnURL,
+		"vpc_MerchTxnRef": req.MerchTxnRef,
+		"vpc_OrderInfo":   req.OrderInfo,
+		"vpc_Amount":      strconv.FormatInt(req.Amount*100, 10),
+		"vpc_TicketNo":    req.ClientIP,
+		"vpc_CallbackURL": req.CallbackURL,
+	}
+	if c.config.SendTimeout && !req.ExpiresAt.IsZero() {
+		timeout := int64(time.Until(req.ExpiresAt).Seconds())
+		if timeout < 1 {
+			timeout = 1
+		}
+		merchantQueryMap["vpc_TimeOut"] = strconv.FormatInt(timeout, 10)
+	}
+	secureHash, err := Sign(merchantQueryMap, c.config.HashCode)
+	if err != nil {
+		return "", err
+	}
+	merchantQueryMap["vpc_SecureHash"] = secureHash
+
+	params := url.Values{}
+	for key, value := range merchantQueryMap {
+		params.Add(key, value)
+	}
+	return c.config.Endpoint + "?" + params.Encode(), nil
+}
+
+// VerifyCallback checks the signature of an IPN or return URL query and parses its result.
+func (c *Client) VerifyCallback(query url.Values) (*PaymentResult, error) {
+	params := flatten(query)
+	if params["vpc_MerchTxnRef"] == "" {
+		return nil, ErrMissingMerchTxnRef
+	}
+	if err := Verify(params, c.config.HashCode); err != nil {
+		return nil, err
+	}
+	result := newPaymentResult(params)
+	result.RawQuery = query.Encode()
+	return result, nil
+}
+
+// QueryDR asks OnePay for the status of a merchant transaction.
+func (c *Client) QueryDR(merchTxnRef string) (*QueryDRResult, error) {
+	params, err := c.post(c.config.QueryDREndpoint, map[string]string{
+		"vpc_Command":     "queryDR",
+		"vpc_Version":     "2",
+		"vpc_MerchTxnRef": merchTxnRef,
+		"vpc_Merchant":    c.config.MerchantID,
+		"vpc_AccessCode":  c.config.AccessCode,
+		"vpc_User":        c.config.User,
+		"vpc_Password":    c.config.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryDRResult{
+		PaymentResult: *newPaymentResult(params),
+		Exists:        params["vpc_DRExists"] == "Y",
+	}
+	if result.MerchTxnRef == "" {
+		result.MerchTxnRef = merchTxnRef
+	}
+	// Unknown transactions are answered without a signature.
+	if result.Exists {
+		if err := Verify(params, c.config.HashCode); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Refund asks OnePay to give back part or all of a successful payment.
+func (c *Client) Refund(req RefundRequest) (*PaymentResult, error) {
+	params, err := c.post(c.config.RefundEndpoint, map[string]string{
+		"vpc_Command":        "refund",
+		"vpc_Version":        "2",
+		"vpc_MerchTxnRef":    req.MerchTxnRef,
+		"vpc_OrgMerchTxnRef": req.OrgMerchTxnRef,
+		"vpc_Amount":         strconv.FormatInt(req.Amount*100, 10),
+		"vpc_Merchant":       c.config.MerchantID,
+		"vpc_AccessCode":     c.config.AccessCode,
+		"vpc_User":           c.config.User,
+		"vpc_Password":       c.config.Password,
+		"vpc_Operator":       req.Operator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if params["vpc_SecureHash"] != "" {
+		if err := Verify(params, c.config.HashCode); err != nil {
+			return nil, err
+		}
+	}
+	return newPaymentResult(params), nil
+}
+
+// post signs params, posts them to a OnePay merchant API endpoint and
+// returns the fields of its query string encoded response.
+func (c *Client) post(endpoint string, merchantQueryMap map[string]string) (map[string]string, error) {
+	secureHash, err := Sign(merchantQueryMap, c.config.HashCode)
+	if err != nil {
+		return nil, err
+	}
+	merchantQueryMap["vpc_SecureHash"] = secureHash
+
+	form := url.Values{}
+	for key, value := range merchantQueryMap {
+		form.Add(key, value)
+	}
+	resp, err := c.httpClient.PostForm(endpoint, form)
+	if err != nil {
+		return nil, errs.ErrOtherService.Wrap(err).Reform("OnePay request failed")
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.ErrOtherService.Wrap(err).Reform("OnePay response read failed")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errs.ErrOtherService.Reform("OnePay returned status %d", resp.StatusCode)
+	}
+	values, err := url.ParseQuery(strings.TrimSpace(string(body)))
+	if err != nil {
+		return nil, errs.ErrOtherService.Wrap(err).Reform("OnePay response malformed")
+	}
+	return flatten(values), nil
+}
+
+func flatten(values url.Values) map[string]string {
+	params := make(map[string]string)
+	for k, v := range values {
+		params[k] = strings.Join(v, "")
+	}
+	return params
+}
+
+func newPaymentResult(params map[string]string) *PaymentResult {
+	amount, _ := strconv.ParseInt(params["vpc_Amount"], 10, 64)
+	raw := url.Values{}
+	for k, v := range params {
+		raw.Set(k, v)
+	}
+	return &PaymentResult{
+		MerchTxnRef:   params["vpc_MerchTxnRef"],
+		OrderInfo:     params["vpc_OrderInfo"],
+		Amount:        amount / 100,
+		ResponseCode:  params["vpc_TxnResponseCode"],
+		Message:       params["vpc_Message"],
+		TransactionNo: params["vpc_TransactionNo"],
+		Params:        params,
+		RawQuery:      raw.Encode(),
+	}
+}
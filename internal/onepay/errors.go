@@ -0,0 +1,8 @@
+package onepay
+
+import errs "ashno-onepay/internal/errors"
+
+var (
+	ErrInvalidSignature   = errs.ErrForbidden.Reform("Invalid signature")
+	ErrMissingMerchTxnRef = errs.ErrBadRequest.Reform("vpc_MerchTxnRef is required")
+)
@@ -0,0 +1,118 @@
+package onepay
+
+// vpc_TxnResponseCode values returned by OnePay.
+const (
+	ResponseCodeSuccess             = "0"
+	ResponseCodeBankDeclined        = "1"
+	ResponseCodeMerchantNotExist    = "3"
+	ResponseCodeInvalidAccessCode   = "4"
+	ResponseCodeInvalidAmount       = "5"
+	ResponseCodeInvalidCurrency     = "6"
+	ResponseCodeUnspecifiedFailure  = "7"
+	ResponseCodeInvalidCardNumber   = "8"
+	ResponseCodeInvalidCardName     = "9"
+	ResponseCodeExpiredCard         = "10"
+	ResponseCodeCardNotRegistered   = "11"
+	ResponseCodeInvalidCardDate     = "12"
+	ResponseCodeExceededLimit       = "13"
+	ResponseCodeInsufficientFunds   = "21"
+	ResponseCodeInvalidAccount      = "22"
+	ResponseCodeAccountLocked       = "23"
+	ResponseCodeInvalidCardInfo     = "24"
+	ResponseCodeInvalidOTP          = "25"
+	ResponseCodeCancelled           = "99"
+	ResponseCodeInProgress          = "100"
+	ResponseCodeTimeout             = "253"
+	ResponseCodePending             = "300"
+	ResponseCodeAuthenticationError = "B"
+	ResponseCodeThreeDSecureFailed  = "F"
+)
+
+var responseDescriptions = map[string]string{
+	ResponseCodeSuccess:             "Approved",
+	ResponseCodeBankDeclined:        "Declined by the issuing bank",
+	ResponseCodeMerchantNotExist:    "Merchant does not exist",
+	ResponseCodeInvalidAccessCode:   "Invalid access code",
+	ResponseCodeInvalidAmount:       "Invalid amount",
+	ResponseCodeInvalidCurrency:     "Invalid currency",
+	ResponseCodeUnspecifiedFailure:  "Unspecified failure",
+	ResponseCodeInvalidCardNumber:   "Invalid card number",
+	ResponseCodeInvalidCardName:     "Invalid card holder name",
+	ResponseCodeExpiredCard:         "Expired card",
+	ResponseCodeCardNotRegistered:   "Card is not registered for online payment",
+	ResponseCodeInvalidCardDate:     "Invalid card date",
+	ResponseCodeExceededLimit:       "Exceeded payment limit",
+	ResponseCodeInsufficientFunds:   "Insufficient funds",
+	ResponseCodeInvalidAccount:      "Invalid account information",
+	ResponseCodeAccountLocked:       "Account locked",
+	ResponseCodeInvalidCardInfo:     "Invalid card information",
+	ResponseCodeInvalidOTP:          "Incorrect OTP",
+	ResponseCodeCancelled:           "Cancelled by the payer",
+	ResponseCodeInProgress:          "Payment in progress",
+	ResponseCodeTimeout:             "Payment timed out",
+	ResponseCodePending:             "Payment pending",
+	ResponseCodeAuthenticationError: "Card authentication failed",
+	ResponseCodeThreeDSecureFailed:  "3-D Secure verification failed",
+}
+
+// Describe returns a human readable meaning of a vpc_TxnResponseCode.
+func Describe(code string) string {
+	if description, ok := responseDescriptions[code]; ok {
+		return description
+	}
+	return "Unknown response code " + code
+}
+
+// IsPending reports whether code means the payer has not finished paying yet.
+func IsPending(code string) bool {
+	return code == "" || code == ResponseCodeInProgress || code == ResponseCodePending
+}
+
+// Outcome is what a vpc_TxnResponseCode means for the payer.
+type Outcome string
+
+const (
+	OutcomeSuccess              Outcome = "success"
+	OutcomePending              Outcome = "pending"
+	OutcomeCancelled            Outcome = "cancelled"
+	OutcomeDeclined             Outcome = "declined"
+	OutcomeInsufficientFunds    Outcome = "insufficient_funds"
+	OutcomeInvalidCard          Outcome = "invalid_card"
+	OutcomeExpiredCard          Outcome = "expired_card"
+	OutcomeAuthenticationFailed Outcome = "authentication_failed"
+	OutcomeTimeout              Outcome = "timeout"
+	OutcomeExpired              Outcome = "expired"
+	OutcomeError                Outcome = "error"
+)
+
+var responseOutcomes = map[string]Outcome{
+	ResponseCodeSuccess:             OutcomeSuccess,
+	ResponseCodeBankDeclined:        OutcomeDeclined,
+	ResponseCodeExceededLimit:       OutcomeDeclined,
+	ResponseCodeAccountLocked:       OutcomeDeclined,
+	ResponseCodeCardNotRegistered:   OutcomeDeclined,
+	ResponseCodeInsufficientFunds:   OutcomeInsufficientFunds,
+	ResponseCodeInvalidCardNumber:   OutcomeInvalidCard,
+	ResponseCodeInvalidCardName:     OutcomeInvalidCard,
+	ResponseCodeInvalidCardDate:     OutcomeInvalidCard,
+	ResponseCodeInvalidAccount:      OutcomeInvalidCard,
+	ResponseCodeInvalidCardInfo:     OutcomeInvalidCard,
+	ResponseCodeExpiredCard:         OutcomeExpiredCard,
+	ResponseCodeInvalidOTP:          OutcomeAuthenticationFailed,
+	ResponseCodeAuthenticationError: OutcomeAuthenticationFailed,
+	ResponseCodeThreeDSecureFailed:  OutcomeAuthenticationFailed,
+	ResponseCodeCancelled:           OutcomeCancelled,
+	ResponseCodeTimeout:             OutcomeTimeout,
+}
+
+// OutcomeOf maps a vpc_TxnResponseCode to the result shown to the payer.
+// Merchant configuration errors and unknown codes are reported as OutcomeError.
+func OutcomeOf(code string) Outcome {
+	if IsPending(code) {
+		return OutcomePending
+	}
+	if outcome, ok := responseOutcomes[code]; ok {
+		return outcome
+	}
+	return OutcomeError
+}
@@ -0,0 +1,54 @@
+package onepay
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Sign computes vpc_SecureHash for params with the merchant hash code. Only non-empty
+// vpc_ and user_ fields take part, sorted by key, as required by OnePay.
+func Sign(params map[string]string, hashCode string) (string, error) {
+	key, err := hex.DecodeString(hashCode)
+	if err != nil {
+		return "", errs.ErrInternal.Wrap(err).Reform("invalid OnePay hash code")
+	}
+	secureHash := hmac.New(sha256.New, key)
+	secureHash.Write([]byte(stringToHash(params)))
+	return strings.ToUpper(hex.EncodeToString(secureHash.Sum(nil))), nil
+}
+
+// Verify checks vpc_SecureHash in params against the merchant hash code.
+func Verify(params map[string]string, hashCode string) error {
+	expected, err := Sign(params, hashCode)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(strings.ToUpper(params["vpc_SecureHash"]))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func stringToHash(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if !strings.HasPrefix(k, "vpc_") && !strings.HasPrefix(k, "user_") {
+			continue
+		}
+		if k == "vpc_SecureHash" || k == "vpc_SecureHashType" || len(v) == 0 {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+params[k])
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AddOnRepository interface {
+	List() ([]*model.AddOn, error)
+	GetByID(ID string) (*model.AddOn, error)
+	GetByCode(code string) (*model.AddOn, error)
+	Create(addOn model.AddOn) (*model.AddOn, error)
+	UpdateDetails(addOn model.AddOn) error
+	ReplacePrices(addOnID string, prices []model.AddOnPrice) error
+	CountReserved(addOnID string, pendingSince time.Time) (int, error)
+	Reserve(items []model.RegistrationLineItem, pendingSince time.Time) error
+	ListLineItems(registrationID string) ([]model.RegistrationLineItem, error)
+	UpdateLineItemsStatus(registrationID, transactionID string, from, to model.LineItemStatus) error
+	CancelLineItems(registrationID string) error
+	RenewLineItem(ID string, unitFeeUSD float64, unitFeeVND int64, pendingSince time.Time) error
+}
+
+type addOnRepository struct {
+	db *gorm.DB
+}
+
+func (r addOnRepository) List() ([]*model.AddOn, error) {
+	var addOns []*model.AddOn
+	err := r.db.Preload("Prices").Order("code ASC").Find(&addOns).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return addOns, nil
+}
+
+func (r addOnRepository) GetByID(ID string) (*model.AddOn, error) {
+	return r.get("id = ?", ID)
+}
+
+func (r addOnRepository) GetByCode(code string) (*model.AddOn, error) {
+	return r.get("code = ?", code)
+}
+
+func (r addOnRepository) get(query string, args ...interface{}) (*model.AddOn, error) {
+	var addOn model.AddOn
+
+	result := r.db.Preload("Prices").Where(query, args...).First(&addOn)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &addOn, nil
+}
+
+func (r addOnRepository) Create(addOn model.AddOn) (*model.AddOn, error) {
+	result := r.db.Create(&addOn)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, errs.ErrInvalidArgument.Reform("add-on %s already exists", addOn.Code)
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &addOn, nil
+}
+
+func (r addOnRepository) UpdateDetails(addOn model.AddOn) error {
+	err := r.db.Model(&model.AddOn{}).
+		Where("id = ?", addOn.Id).
+		Updates(map[string]interface{}{
+			"name":        addOn.Name,
+			"description": addOn.Description,
+			"capacity":    addOn.Capacity,
+			"active":      addOn.Active,
+			"updated_at":  time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r addOnRepository) ReplacePrices(addOnID string, prices []model.AddOnPrice) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("add_on_id = ?", addOnID).Delete(&model.AddOnPrice{}).Error; err != nil {
+			return err
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+		return tx.Create(&prices).Error
+	})
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// CountReserved counts the paid units of an add-on and the pending ones ordered or repriced
+// after pendingSince, i.e. whose payment link may still be paid.
+func (r addOnRepository) CountReserved(addOnID string, pendingSince time.Time) (int, error) {
+	count, err := countReserved(r.db, addOnID, pendingSince)
+	if err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return count, nil
+}
+
+func countReserved(db *gorm.DB, addOnID string, pendingSince time.Time) (int, error) {
+	var count int
+	err := db.Model(&model.RegistrationLineItem{}).
+		Where("add_on_id = ?", addOnID).
+		Where("status = ? OR (status = ? AND updated_at > ?)",
+			string(model.LineItemStatusDone), string(model.LineItemStatusPending), pendingSince.UTC()).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&count).Error
+	return count, err
+}
+
+// Reserve stores the line items if every add-on they are for has enough capacity left.
+// The add-ons are locked while counting so concurrent orders cannot oversell them.
+func (r addOnRepository) Reserve(items []model.RegistrationLineItem, pendingSince time.Time) error {
+	if len(items) == 0 {
+		return nil
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		requested := map[string]int{}
+		for _, item := range items {
+			requested[item.AddOnID] += item.Quantity
+		}
+		for addOnID, quantity := range requested {
+			var addOn model.AddOn
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", addOnID).First(&addOn).Error
+			if err != nil {
+				return err
+			}
+			if addOn.Capacity == 0 {
+				continue
+			}
+			reserved, err := countReserved(tx, addOnID, pendingSince)
+			if err != nil {
+				return err
+			}
+			if reserved+quantity > addOn.Capacity {
+				return errs.ErrSoldOut.Reform("%s is sold out, %d left", addOn.Name, max(addOn.Capacity-reserved, 0))
+			}
+		}
+		return tx.Create(&items).Error
+	})
+	if err != nil {
+		if appErr, ok := err.(errs.AppError); ok {
+			return appErr
+		}
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r addOnRepository) ListLineItems(registrationID string) ([]model.RegistrationLineItem, error) {
+	var items []model.RegistrationLineItem
+	err := r.db.Where("registration_id = ?", registrationID).Order("created_at ASC").Find(&items).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return items, nil
+}
+
+func (r addOnRepository) UpdateLineItemsStatus(registrationID, transactionID string, from, to model.LineItemStatus) error {
+	err := r.db.Model(&model.RegistrationLineItem{}).
+		Where("registration_id = ? AND transaction_id = ? AND status = ?", registrationID, transactionID, string(from)).
+		Updates(map[string]interface{}{
+			"status":     string(to),
+			"updated_at": time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// CancelLineItems cancels every pending or paid item of a registration, freeing their capacity.
+func (r addOnRepository) CancelLineItems(registrationID string) error {
+	err := r.db.Model(&model.RegistrationLineItem{}).
+		Where("registration_id = ? AND status IN ?", registrationID,
+			[]string{string(model.LineItemStatusPending), string(model.LineItemStatusDone)}).
+		Updates(map[string]interface{}{
+			"status":     string(model.LineItemStatusCancelled),
+			"updated_at": time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// RenewLineItem reprices a pending or failed item and renews its reservation if its add-on still
+// has capacity for it, locking the add-on like Reserve does.
+func (r addOnRepository) RenewLineItem(ID string, unitFeeUSD float64, unitFeeVND int64, pendingSince time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var item model.RegistrationLineItem
+		if err := tx.Where("id = ?", ID).First(&item).Error; err != nil {
+			return err
+		}
+		var addOn model.AddOn
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", item.AddOnID).First(&addOn).Error
+		if err != nil {
+			return err
+		}
+		if addOn.Capacity != 0 {
+			reserved, err := countReserved(tx, addOn.Id, pendingSince)
+			if err != nil {
+				return err
+			}
+			// an item whose reservation has not lapsed is counted already
+			if item.Status == string(model.LineItemStatusPending) && item.UpdatedAt.After(pendingSince.UTC()) {
+				reserved -= item.Quantity
+			}
+			if reserved+item.Quantity > addOn.Capacity {
+				return errs.ErrSoldOut.Reform("%s is sold out, %d left", addOn.Name, max(addOn.Capacity-reserved, 0))
+			}
+		}
+		return tx.Model(&model.RegistrationLineItem{}).
+			Where("id = ? AND status IN ?", ID,
+				[]string{string(model.LineItemStatusPending), string(model.LineItemStatusFail)}).
+			Updates(map[string]interface{}{
+				"unit_fee_usd": unitFeeUSD,
+				"unit_fee_vnd": unitFeeVND,
+				"status":       string(model.LineItemStatusPending),
+				"updated_at":   time.Now().UTC(),
+			}).Error
+	})
+	if err != nil {
+		if appErr, ok := err.(errs.AppError); ok {
+			return appErr
+		}
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var addOnRepositoryInstance *addOnRepository
+var addOnRepositoryOnce sync.Once
+
+func GetAddOnRepositoryInstance(db *gorm.DB) AddOnRepository {
+	addOnRepositoryOnce.Do(func() {
+		addOnRepositoryInstance = &addOnRepository{
+			db: db,
+		}
+	})
+	return addOnRepositoryInstance
+}
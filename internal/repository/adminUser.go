@@ -0,0 +1,112 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AdminUserRepository interface {
+	List() ([]*model.AdminUser, error)
+	GetByID(ID string) (*model.AdminUser, error)
+	GetByEmail(email string) (*model.AdminUser, error)
+	Count() (int64, error)
+	Create(user model.AdminUser) (*model.AdminUser, error)
+	Update(user model.AdminUser) error
+	UpdateLastLogin(ID string, at time.Time) error
+}
+
+type adminUserRepository struct {
+	db *gorm.DB
+}
+
+func (r adminUserRepository) List() ([]*model.AdminUser, error) {
+	var users []*model.AdminUser
+	err := r.db.Order("email ASC").Find(&users).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return users, nil
+}
+
+func (r adminUserRepository) GetByID(ID string) (*model.AdminUser, error) {
+	return r.get("id = ?", ID)
+}
+
+func (r adminUserRepository) GetByEmail(email string) (*model.AdminUser, error) {
+	return r.get("email = ?", model.NormalizeEmail(email))
+}
+
+func (r adminUserRepository) get(query string, args ...interface{}) (*model.AdminUser, error) {
+	var user model.AdminUser
+
+	result := r.db.Where(query, args...).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &user, nil
+}
+
+func (r adminUserRepository) Count() (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.AdminUser{}).Count(&count).Error; err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return count, nil
+}
+
+func (r adminUserRepository) Create(user model.AdminUser) (*model.AdminUser, error) {
+	result := r.db.Create(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, errs.ErrInvalidArgument.Reform("admin user %s already exists", user.Email)
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &user, nil
+}
+
+func (r adminUserRepository) Update(user model.AdminUser) error {
+	err := r.db.Model(&model.AdminUser{}).
+		Where("id = ?", user.Id).
+		Updates(map[string]interface{}{
+			"name":          user.Name,
+			"password_hash": user.PasswordHash,
+			"role":          user.Role,
+			"active":        user.Active,
+			"updated_at":    time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r adminUserRepository) UpdateLastLogin(ID string, at time.Time) error {
+	err := r.db.Model(&model.AdminUser{}).
+		Where("id = ?", ID).
+		UpdateColumn("last_login_at", at.UTC()).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var adminUserRepositoryInstance *adminUserRepository
+var adminUserRepositoryOnce sync.Once
+
+func GetAdminUserRepositoryInstance(db *gorm.DB) AdminUserRepository {
+	adminUserRepositoryOnce.Do(func() {
+		adminUserRepositoryInstance = &adminUserRepository{
+			db: db,
+		}
+	})
+	return adminUserRepositoryInstance
+}
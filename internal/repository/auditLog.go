@@ -0,0 +1,48 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(entry model.AuditLog) error
+	ListByRegistrationID(registrationID string) ([]*model.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func (r auditLogRepository) Create(entry model.AuditLog) error {
+	if err := r.db.Create(&entry).Error; err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r auditLogRepository) ListByRegistrationID(registrationID string) ([]*model.AuditLog, error) {
+	var entries []*model.AuditLog
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return entries, nil
+}
+
+var auditLogRepositoryInstance *auditLogRepository
+var auditLogRepositoryOnce sync.Once
+
+func GetAuditLogRepositoryInstance(db *gorm.DB) AuditLogRepository {
+	auditLogRepositoryOnce.Do(func() {
+		auditLogRepositoryInstance = &auditLogRepository{
+			db: db,
+		}
+	})
+	return auditLogRepositoryInstance
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CategoryCapacityRepository interface {
+	List() ([]*model.CategoryCapacity, error)
+	Get(category string) (*model.CategoryCapacity, error)
+	Replace(capacities []model.CategoryCapacity) error
+}
+
+type categoryCapacityRepository struct {
+	db *gorm.DB
+}
+
+// List returns the capacities with the seats reserved and the attendees waiting for each.
+func (r categoryCapacityRepository) List() ([]*model.CategoryCapacity, error) {
+	var capacities []*model.CategoryCapacity
+	if err := r.db.Order("category ASC").Find(&capacities).Error; err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	for _, capacity := range capacities {
+		if err := r.count(capacity); err != nil {
+			return nil, err
+		}
+	}
+	return capacities, nil
+}
+
+// Get returns the capacity of a category with its seats reserved and attendees waiting,
+// nil when the category is unlimited.
+func (r categoryCapacityRepository) Get(category string) (*model.CategoryCapacity, error) {
+	var capacity model.CategoryCapacity
+	result := r.db.Where("category = ?", category).First(&capacity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	if err := r.count(&capacity); err != nil {
+		return nil, err
+	}
+	return &capacity, nil
+}
+
+func (r categoryCapacityRepository) count(capacity *model.CategoryCapacity) error {
+	var err error
+	if capacity.Reserved, err = countSeats(r.db, capacity.Category, "", time.Now()); err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	if capacity.Waiting, err = countWaiting(r.db, capacity.Category); err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r categoryCapacityRepository) Replace(capacities []model.CategoryCapacity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.CategoryCapacity{}).Error; err != nil {
+			return err
+		}
+		if len(capacities) == 0 {
+			return nil
+		}
+		return tx.Create(&capacities).Error
+	})
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// countSeats counts the registrations of a category that are paid or unpaid but still holding their seat at now.
+func countSeats(db *gorm.DB, category, exceptRegistrationID string, now time.Time) (int, error) {
+	var count int64
+	err := db.Model(&model.Registration{}).
+		Joins("JOIN registration_options ON registration_options.id = registrations.registration_option_id").
+		Where("registration_options.category = ? AND registrations.id <> ?", category, exceptRegistrationID).
+		Where("registrations.payment_status IN ? OR (registrations.payment_status = ? AND registrations.reserved_until > ?)",
+			paidStatuses(), string(model.PaymentStatusPending), now.UTC()).
+		Count(&count).Error
+	return int(count), err
+}
+
+// reserveSeat fails with ErrSoldOut when a category has no seat left for a registration, heldSeats
+// being promised to the waitlist. The capacity is locked until tx ends so concurrent registrations
+// cannot oversell it; categories without a capacity are unlimited.
+func reserveSeat(tx *gorm.DB, category, registrationID string, heldSeats int) error {
+	var capacity model.CategoryCapacity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("category = ?", category).First(&capacity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if capacity.Capacity == 0 {
+		return nil
+	}
+	reserved, err := countSeats(tx, category, registrationID, time.Now())
+	if err != nil {
+		return err
+	}
+	if reserved+heldSeats >= capacity.Capacity {
+		return errs.ErrSoldOut.Reform("%s is sold out, join the waitlist to be offered a seat when one frees up", category)
+	}
+	return nil
+}
+
+var categoryCapacityRepositoryInstance *categoryCapacityRepository
+var categoryCapacityRepositoryOnce sync.Once
+
+func GetCategoryCapacityRepositoryInstance(db *gorm.DB) CategoryCapacityRepository {
+	categoryCapacityRepositoryOnce.Do(func() {
+		categoryCapacityRepositoryInstance = &categoryCapacityRepository{
+			db: db,
+		}
+	})
+	return categoryCapacityRepositoryInstance
+}
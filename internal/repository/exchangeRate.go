@@ -0,0 +1,66 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository interface {
+	Get(base, quote string) (*model.ExchangeRate, error)
+	Save(rate model.ExchangeRate) (*model.ExchangeRate, error)
+	Delete(base, quote string) error
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func (r exchangeRateRepository) Get(base, quote string) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+
+	result := r.db.Where("base = ? AND quote = ?", base, quote).First(&rate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &rate, nil
+}
+
+// Save inserts the rate or replaces the one already set for the same currency pair.
+func (r exchangeRateRepository) Save(rate model.ExchangeRate) (*model.ExchangeRate, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_by", "updated_at"}),
+	}).Create(&rate)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return r.Get(rate.Base, rate.Quote)
+}
+
+func (r exchangeRateRepository) Delete(base, quote string) error {
+	err := r.db.Where("base = ? AND quote = ?", base, quote).Delete(&model.ExchangeRate{}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var exchangeRateRepositoryInstance *exchangeRateRepository
+var exchangeRateRepositoryOnce sync.Once
+
+func GetExchangeRateRepositoryInstance(db *gorm.DB) ExchangeRateRepository {
+	exchangeRateRepositoryOnce.Do(func() {
+		exchangeRateRepositoryInstance = &exchangeRateRepository{
+			db: db,
+		}
+	})
+	return exchangeRateRepositoryInstance
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PaymentTransactionRepository interface {
+	Create(transaction model.PaymentTransaction) (*model.PaymentTransaction, error)
+	GetByMerchTxnRef(merchTxnRef, orderInfo string) (*model.PaymentTransaction, error)
+	CompletePending(ID string, result model.PaymentTransactionResult) (bool, error)
+	ResetPending(ID string) error
+	ExpirePending(registrationID, message string) error
+	ListByRegistrationID(registrationID string) ([]*model.PaymentTransaction, error)
+	ListPending(createdBefore time.Time, limit int) ([]*model.PaymentTransaction, error)
+}
+
+type paymentTransactionRepository struct {
+	db *gorm.DB
+}
+
+func (r paymentTransactionRepository) Create(transaction model.PaymentTransaction) (*model.PaymentTransaction, error) {
+	result := r.db.Create(&transaction)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &transaction, nil
+}
+
+func (r paymentTransactionRepository) GetByMerchTxnRef(merchTxnRef, orderInfo string) (*model.PaymentTransaction, error) {
+	var transaction model.PaymentTransaction
+
+	result := r.db.Where("merch_txn_ref = ? AND order_info = ?", merchTxnRef, orderInfo).First(&transaction)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &transaction, nil
+}
+
+// CompletePending stores the result only if the transaction is still pending and reports
+// whether this call was the one that completed it, so concurrent callbacks apply it once.
+func (r paymentTransactionRepository) CompletePending(ID string, result model.PaymentTransactionResult) (bool, error) {
+	now := time.Now().UTC()
+	query := r.db.Model(&model.PaymentTransaction{}).
+		Where("id = ? AND status = ?", ID, string(model.PaymentTransactionStatusPending)).
+		Updates(map[string]interface{}{
+			"status":        result.Status,
+			"response_code": result.ResponseCode,
+			"message":       result.Message,
+			"raw_query":     result.RawQuery,
+			"completed_at":  now,
+			"updated_at":    now,
+		})
+	if query.Error != nil {
+		return false, errs.ErrInternal.Wrap(query.Error)
+	}
+	return query.RowsAffected == 1, nil
+}
+
+func (r paymentTransactionRepository) ResetPending(ID string) error {
+	err := r.db.Model(&model.PaymentTransaction{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"status":       string(model.PaymentTransactionStatusPending),
+			"completed_at": nil,
+			"updated_at":   time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r paymentTransactionRepository) ExpirePending(registrationID, message string) error {
+	now := time.Now().UTC()
+	err := r.db.Model(&model.PaymentTransaction{}).
+		Where("registration_id = ? AND order_type = ? AND status = ?", registrationID,
+			string(model.OrderTypeRegistration), string(model.PaymentTransactionStatusPending)).
+		Updates(map[string]interface{}{
+			"status":       string(model.PaymentTransactionStatusExpired),
+			"message":      message,
+			"expires_at":   now,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r paymentTransactionRepository) ListByRegistrationID(registrationID string) ([]*model.PaymentTransaction, error) {
+	var transactions []*model.PaymentTransaction
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("created_at DESC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return transactions, nil
+}
+
+func (r paymentTransactionRepository) ListPending(createdBefore time.Time, limit int) ([]*model.PaymentTransaction, error) {
+	var transactions []*model.PaymentTransaction
+	err := r.db.Where("status = ? AND created_at <= ?", string(model.PaymentTransactionStatusPending), createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return transactions, nil
+}
+
+var paymentTransactionRepositoryInstance *paymentTransactionRepository
+var paymentTransactionRepositoryOnce sync.Once
+
+func GetPaymentTransactionRepositoryInstance(db *gorm.DB) PaymentTransactionRepository {
+	paymentTransactionRepositoryOnce.Do(func() {
+		paymentTransactionRepositoryInstance = &paymentTransactionRepository{
+			db: db,
+		}
+	})
+	return paymentTransactionRepositoryInstance
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PeriodRepository interface {
+	List() ([]*model.Period, error)
+	FindActive(at time.Time) (*model.Period, error)
+	ReplaceAll(periods []model.Period) ([]*model.Period, error)
+}
+
+type periodRepository struct {
+	db *gorm.DB
+}
+
+func (r periodRepository) List() ([]*model.Period, error) {
+	var periods []*model.Period
+	err := r.db.Order("start_at ASC").Find(&periods).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return periods, nil
+}
+
+func (r periodRepository) FindActive(at time.Time) (*model.Period, error) {
+	var period model.Period
+
+	result := r.db.Where("start_at <= ? AND end_at > ?", at.UTC(), at.UTC()).First(&period)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &period, nil
+}
+
+// ReplaceAll swaps the whole schedule in one transaction, so it is never seen half edited.
+func (r periodRepository) ReplaceAll(periods []model.Period) ([]*model.Period, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.Period{}).Error; err != nil {
+			return err
+		}
+		if len(periods) == 0 {
+			return nil
+		}
+		return tx.Create(&periods).Error
+	})
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return r.List()
+}
+
+var periodRepositoryInstance *periodRepository
+var periodRepositoryOnce sync.Once
+
+func GetPeriodRepositoryInstance(db *gorm.DB) PeriodRepository {
+	periodRepositoryOnce.Do(func() {
+		periodRepositoryInstance = &periodRepository{
+			db: db,
+		}
+	})
+	return periodRepositoryInstance
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromoCodeRepository interface {
+	List() ([]*model.PromoCode, error)
+	GetByID(ID string) (*model.PromoCode, error)
+	GetByCode(code string) (*model.PromoCode, error)
+	Create(promoCode model.PromoCode) (*model.PromoCode, error)
+	Update(promoCode model.PromoCode) error
+	CountUses(promoCodeID, exceptRegistrationID string, pendingSince time.Time) (int, error)
+	Redeem(promoCodeID string, registration model.Registration, pendingSince time.Time) error
+}
+
+type promoCodeRepository struct {
+	db *gorm.DB
+}
+
+func (r promoCodeRepository) List() ([]*model.PromoCode, error) {
+	var promoCodes []*model.PromoCode
+	err := r.db.Order("code ASC").Find(&promoCodes).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	var uses []struct {
+		PromoCodeID string
+		Used        int64
+	}
+	err = r.db.Model(&model.Registration{}).
+		Select("promo_code_id, COUNT(*) AS used").
+		Where("promo_code_id <> '' AND payment_status IN ?", paidStatuses()).
+		Group("promo_code_id").
+		Scan(&uses).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	used := map[string]int64{}
+	for _, u := range uses {
+		used[u.PromoCodeID] = u.Used
+	}
+	for _, promoCode := range promoCodes {
+		promoCode.Used = used[promoCode.Id]
+	}
+	return promoCodes, nil
+}
+
+func (r promoCodeRepository) GetByID(ID string) (*model.PromoCode, error) {
+	return r.get("id = ?", ID)
+}
+
+func (r promoCodeRepository) GetByCode(code string) (*model.PromoCode, error) {
+	return r.get("code = ?", model.NormalizePromoCode(code))
+}
+
+func (r promoCodeRepository) get(query string, args ...interface{}) (*model.PromoCode, error) {
+	var promoCode model.PromoCode
+
+	result := r.db.Where(query, args...).First(&promoCode)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &promoCode, nil
+}
+
+func (r promoCodeRepository) Create(promoCode model.PromoCode) (*model.PromoCode, error) {
+	result := r.db.Create(&promoCode)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return nil, errs.ErrInvalidArgument.Reform("promo code %s already exists", promoCode.Code)
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &promoCode, nil
+}
+
+func (r promoCodeRepository) Update(promoCode model.PromoCode) error {
+	err := r.db.Model(&model.PromoCode{}).
+		Where("id = ?", promoCode.Id).
+		Updates(map[string]interface{}{
+			"description": promoCode.Description,
+			"type":        promoCode.Type,
+			"percent":     promoCode.Percent,
+			"amount_usd":  promoCode.AmountUSD,
+			"amount_vnd":  promoCode.AmountVND,
+			"max_uses":    promoCode.MaxUses,
+			"valid_from":  promoCode.ValidFrom,
+			"valid_until": promoCode.ValidUntil,
+			"categories":  promoCode.Categories,
+			"email":       promoCode.Email,
+			"active":      promoCode.Active,
+			"updated_at":  time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// CountUses counts the paid registrations made with a promo code and the unpaid ones that
+// applied it after pendingSince, i.e. whose payment link may still be paid.
+func (r promoCodeRepository) CountUses(promoCodeID, exceptRegistrationID string, pendingSince time.Time) (int, error) {
+	count, err := countUses(r.db, promoCodeID, exceptRegistrationID, pendingSince)
+	if err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return count, nil
+}
+
+func countUses(db *gorm.DB, promoCodeID, exceptRegistrationID string, pendingSince time.Time) (int, error) {
+	var count int64
+	err := db.Model(&model.Registration{}).
+		Where("promo_code_id = ? AND id <> ?", promoCodeID, exceptRegistrationID).
+		Where("payment_status IN ? OR (payment_status = ? AND updated_at > ?)",
+			paidStatuses(), string(model.PaymentStatusPending), pendingSince.UTC()).
+		Count(&count).Error
+	return int(count), err
+}
+
+// Redeem applies a promo code to a stored registration if the code has uses left.
+// The code is locked while counting so concurrent registrations cannot exceed MaxUses.
+func (r promoCodeRepository) Redeem(promoCodeID string, registration model.Registration, pendingSince time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.PromoCode
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", promoCodeID).First(&locked).Error
+		if err != nil {
+			return err
+		}
+		if locked.MaxUses > 0 {
+			used, err := countUses(tx, locked.Id, registration.Id, pendingSince)
+			if err != nil {
+				return err
+			}
+			if used >= locked.MaxUses {
+				return errs.ErrPromoCode.Reform("promo code %s has been used up", locked.Code)
+			}
+		}
+		return tx.Model(&model.Registration{}).
+			Where("id = ?", registration.Id).
+			Updates(map[string]interface{}{
+				"promo_code_id": locked.Id,
+				"promo_code":    locked.Code,
+				"discount_usd":  registration.DiscountUSD,
+				"discount_vnd":  registration.DiscountVND,
+				"updated_at":    time.Now().UTC(),
+			}).Error
+	})
+	if err != nil {
+		if appErr, ok := err.(errs.AppError); ok {
+			return appErr
+		}
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func paidStatuses() []string {
+	return []string{string(model.PaymentStatusDone), string(model.PaymentStatusPartiallyRefunded)}
+}
+
+var promoCodeRepositoryInstance *promoCodeRepository
+var promoCodeRepositoryOnce sync.Once
+
+func GetPromoCodeRepositoryInstance(db *gorm.DB) PromoCodeRepository {
+	promoCodeRepositoryOnce.Do(func() {
+		promoCodeRepositoryInstance = &promoCodeRepository{
+			db: db,
+		}
+	})
+	return promoCodeRepositoryInstance
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"ashno-onepay/internal/model"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// insertedValues builds the INSERT of create without a database and returns its values by column.
+func insertedValues(t *testing.T, create func(db *gorm.DB) error) map[string]interface{} {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=ashno"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("open dry run database: %v", err)
+	}
+	var statement *gorm.Statement
+	err = db.Callback().Create().After("gorm:create").Register("test:statement", func(tx *gorm.DB) {
+		statement = tx.Statement
+	})
+	if err != nil {
+		t.Fatalf("register create callback: %v", err)
+	}
+	if err := create(db); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if statement == nil {
+		t.Fatal("no INSERT was built")
+	}
+
+	sql := statement.SQL.String()
+	columns := strings.Split(sql[strings.Index(sql, "(")+1:strings.Index(sql, ")")], ",")
+	values := map[string]interface{}{}
+	for i, column := range columns {
+		if i < len(statement.Vars) {
+			values[strings.Trim(column, `" `)] = statement.Vars[i]
+		}
+	}
+	return values
+}
+
+func TestPromoCodeRepositoryCreateInactive(t *testing.T) {
+	values := insertedValues(t, func(db *gorm.DB) error {
+		_, err := promoCodeRepository{db: db}.Create(model.PromoCode{
+			Code:    "SPEAKER",
+			Type:    model.DiscountTypeWaiver,
+			Active:  false,
+			MaxUses: 1,
+		})
+		return err
+	})
+	active, ok := values["active"]
+	if !ok {
+		t.Fatalf("active is not inserted, the column default would apply: %v", values)
+	}
+	if active != false {
+		t.Errorf("active = %v, want false", active)
+	}
+}
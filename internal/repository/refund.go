@@ -0,0 +1,88 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type RefundRepository interface {
+	Create(refund model.Refund) (*model.Refund, error)
+	UpdateResult(ID string, result model.PaymentTransactionResult) error
+	SumSucceededByTransaction(paymentTransactionID string) (int64, error)
+	// SumPendingByTransaction sums the refunds OnePay has not answered yet, which may still go through.
+	SumPendingByTransaction(paymentTransactionID string) (int64, error)
+	ListByRegistrationID(registrationID string) ([]*model.Refund, error)
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func (r refundRepository) Create(refund model.Refund) (*model.Refund, error) {
+	result := r.db.Create(&refund)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &refund, nil
+}
+
+func (r refundRepository) UpdateResult(ID string, result model.PaymentTransactionResult) error {
+	err := r.db.Model(&model.Refund{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"status":        result.Status,
+			"response_code": result.ResponseCode,
+			"message":       result.Message,
+			"raw_query":     result.RawQuery,
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r refundRepository) SumSucceededByTransaction(paymentTransactionID string) (int64, error) {
+	return r.sumByTransaction(paymentTransactionID, model.RefundStatusSuccess)
+}
+
+func (r refundRepository) SumPendingByTransaction(paymentTransactionID string) (int64, error) {
+	return r.sumByTransaction(paymentTransactionID, model.RefundStatusPending)
+}
+
+func (r refundRepository) sumByTransaction(paymentTransactionID string, status model.RefundStatus) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.Refund{}).
+		Where("payment_transaction_id = ? AND status = ?", paymentTransactionID, string(status)).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return total, nil
+}
+
+func (r refundRepository) ListByRegistrationID(registrationID string) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("created_at DESC").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return refunds, nil
+}
+
+var refundRepositoryInstance *refundRepository
+var refundRepositoryOnce sync.Once
+
+func GetRefundRepositoryInstance(db *gorm.DB) RefundRepository {
+	refundRepositoryOnce.Do(func() {
+		refundRepositoryInstance = &refundRepository{
+			db: db,
+		}
+	})
+	return refundRepositoryInstance
+}
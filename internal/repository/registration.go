@@ -11,10 +11,17 @@ import (
 )
 
 type RegistrationRepository interface {
-	Create(registration model.Registration) (*model.Registration, error)
+	Create(registration model.Registration, heldSeats int) (*model.Registration, error)
+	RenewSeat(ID, category string, reservedUntil time.Time, heldSeats int) error
 	GetByEmail(email string) (*model.Registration, error)
 	GetRegistration(ID string) (*model.Registration, error)
 	UpdatePaymentStatus(ID, status string) error
+	UpdatePaymentMethod(ID, method string) error
+	UpdateAttendee(ID string, attendee model.Attendee, ticketCode string) error
+	UpdateDetails(ID string, attendee model.Attendee) error
+	CheckIn(ID, operatorID string, at time.Time) (bool, error)
+	Restore(registration model.Registration) error
+	UpdateRegistrationOption(ID, optionID string) error
 	Remove(ID string) error
 	UpdateAccompanyPersonsByID(id string, accompanyPersons model.AccompanyPersonList) error
 	SaveAccompanyPersons(persons []model.AccompanyPersonDB) error
@@ -37,6 +44,85 @@ func (r registrationRepository) UpdatePaymentStatus(ID, status string) error {
 	return err
 }
 
+func (r registrationRepository) UpdatePaymentMethod(ID, method string) error {
+	err := r.db.Model(&model.Registration{}).
+		Where("id = ?", ID).
+		Update("payment_method", method).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// UpdateAttendee hands a registration over to another attendee with a new ticket.
+func (r registrationRepository) UpdateAttendee(ID string, attendee model.Attendee, ticketCode string) error {
+	err := r.db.Model(&model.Registration{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"doctorate_degree": attendee.DoctorateDegree,
+			"first_name":       attendee.FirstName,
+			"middle_name":      attendee.MiddleName,
+			"last_name":        attendee.LastName,
+			"date_of_birth":    attendee.DateOfBirth,
+			"institution":      attendee.Institution,
+			"email":            attendee.Email,
+			"phone_number":     attendee.PhoneNumber,
+			"ticket_code":      ticketCode,
+			"updated_at":       time.Now().UTC(),
+		}).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errs.ErrInvalidArgument.Reform("email %s is already registered", attendee.Email)
+		}
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// UpdateDetails corrects the details of the attendee of a registration, keeping their email and ticket.
+func (r registrationRepository) UpdateDetails(ID string, attendee model.Attendee) error {
+	err := r.db.Model(&model.Registration{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"doctorate_degree": attendee.DoctorateDegree,
+			"first_name":       attendee.FirstName,
+			"middle_name":      attendee.MiddleName,
+			"last_name":        attendee.LastName,
+			"date_of_birth":    attendee.DateOfBirth,
+			"institution":      attendee.Institution,
+			"phone_number":     attendee.PhoneNumber,
+			"updated_at":       time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// CheckIn records that the attendee arrived, returning false if they were checked in already.
+func (r registrationRepository) CheckIn(ID, operatorID string, at time.Time) (bool, error) {
+	result := r.db.Model(&model.Registration{}).
+		Where("id = ? AND checked_in_at IS NULL", ID).
+		Updates(map[string]interface{}{
+			"checked_in_at": at.UTC(),
+			"checked_in_by": operatorID,
+		})
+	if result.Error != nil {
+		return false, errs.ErrInternal.Wrap(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r registrationRepository) UpdateRegistrationOption(ID, optionID string) error {
+	err := r.db.Model(&model.Registration{}).
+		Where("id = ?", ID).
+		Update("registration_option_id", optionID).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
 func (r registrationRepository) UpdateAccompanyPersonsByID(id string, accompanyPersons model.AccompanyPersonList) error {
 	return r.db.Model(&model.Registration{}).
 		Where("id = ?", id).
@@ -53,7 +139,7 @@ func (r registrationRepository) SaveAccompanyPersons(persons []model.AccompanyPe
 func (r registrationRepository) GetByEmail(email string) (*model.Registration, error) {
 	var registration model.Registration
 
-	result := r.db.Where("email = ?", email).First(&registration)
+	result := r.db.Preload("LineItems").Where("email = ?", email).First(&registration)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -67,7 +153,7 @@ func (r registrationRepository) GetByEmail(email string) (*model.Registration, e
 func (r registrationRepository) GetRegistration(ID string) (*model.Registration, error) {
 	var registration model.Registration
 
-	result := r.db.Preload("RegistrationOption").Where("id = ?", ID).First(&registration)
+	result := r.db.Preload("RegistrationOption").Preload("LineItems").Where("id = ?", ID).First(&registration)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errs.ErrNotFound.Reform("registration not found")
@@ -78,14 +164,70 @@ func (r registrationRepository) GetRegistration(ID string) (*model.Registration,
 	return &registration, nil
 }
 
-func (r registrationRepository) Create(registration model.Registration) (*model.Registration, error) {
-	result := r.db.Create(&registration)
-	if result.Error != nil {
-		return nil, errs.ErrInternal.Wrap(result.Error)
+// Create stores the registration without its line items, which are reserved by AddOnRepository,
+// if its category has a seat left besides the heldSeats promised to the waitlist.
+func (r registrationRepository) Create(registration model.Registration, heldSeats int) (*model.Registration, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveSeat(tx, registration.RegistrationOption.Category, registration.Id, heldSeats); err != nil {
+			return err
+		}
+		return tx.Omit("LineItems").Create(&registration).Error
+	})
+	if err != nil {
+		if appErr, ok := err.(errs.AppError); ok {
+			return nil, appErr
+		}
+		return nil, errs.ErrInternal.Wrap(err)
 	}
 	return &registration, nil
 }
 
+// Restore stores a removed registration again as it was, with its line items. It takes no seat
+// check, as the registration held its seat until it was removed.
+func (r registrationRepository) Restore(registration model.Registration) error {
+	err := r.db.Session(&gorm.Session{SkipHooks: true}).Omit("RegistrationOption").Create(&registration).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+// RenewSeat holds the seat of an unpaid registration until reservedUntil, or later when it already
+// does, and moves it back to pending. A registration whose seat lapsed or was freed by a failed
+// payment only gets one if its category has a seat left besides the heldSeats promised to the waitlist.
+func (r registrationRepository) RenewSeat(ID, category string, reservedUntil time.Time, heldSeats int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var registration model.Registration
+		if err := tx.Where("id = ?", ID).First(&registration).Error; err != nil {
+			return err
+		}
+		holding := registration.PaymentStatus == string(model.PaymentStatusPending) &&
+			registration.ReservedUntil != nil && registration.ReservedUntil.After(time.Now())
+		if holding && registration.ReservedUntil.After(reservedUntil) {
+			reservedUntil = *registration.ReservedUntil
+		}
+		if !holding {
+			if err := reserveSeat(tx, category, ID, heldSeats); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Registration{}).
+			Where("id = ?", ID).
+			Updates(map[string]interface{}{
+				"payment_status": string(model.PaymentStatusPending),
+				"reserved_until": reservedUntil.UTC(),
+				"updated_at":     time.Now().UTC(),
+			}).Error
+	})
+	if err != nil {
+		if appErr, ok := err.(errs.AppError); ok {
+			return appErr
+		}
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
 func (r registrationRepository) GetAccompanyPersonsByTransactionAndRegistration(transactionID string) ([]model.AccompanyPersonDB, error) {
 	var persons []model.AccompanyPersonDB
 	err := r.db.Where("transaction_id = ?", transactionID).Find(&persons).Error
@@ -94,7 +236,7 @@ func (r registrationRepository) GetAccompanyPersonsByTransactionAndRegistration(
 
 func (r registrationRepository) GetRegistrations(startTime, endTime time.Time) ([]*model.Registration, error) {
 	var registrations []*model.Registration
-	query := r.db.Preload("RegistrationOption")
+	query := r.db.Preload("RegistrationOption").Preload("LineItems")
 	query = query.Where("payment_status = ?", model.PaymentStatusDone)
 
 	if !startTime.IsZero() && !endTime.IsZero() {
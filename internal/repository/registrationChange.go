@@ -0,0 +1,131 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RegistrationChangeRepository interface {
+	Create(change model.RegistrationChange) (*model.RegistrationChange, error)
+	Get(ID string) (*model.RegistrationChange, error)
+	List(status string) ([]*model.RegistrationChange, error)
+	ListByRegistrationID(registrationID string) ([]*model.RegistrationChange, error)
+	Decide(change model.RegistrationChange) (bool, error)
+	ResetPending(ID string) error
+	SetRefund(ID, refundID string, refundAmount int64) error
+}
+
+type registrationChangeRepository struct {
+	db *gorm.DB
+}
+
+func (r registrationChangeRepository) Create(change model.RegistrationChange) (*model.RegistrationChange, error) {
+	result := r.db.Create(&change)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &change, nil
+}
+
+func (r registrationChangeRepository) Get(ID string) (*model.RegistrationChange, error) {
+	var change model.RegistrationChange
+
+	result := r.db.Where("id = ?", ID).First(&change)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrNotFound.Reform("registration change not found")
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &change, nil
+}
+
+// List returns the changes with a status, every change when it is empty, oldest first.
+func (r registrationChangeRepository) List(status string) ([]*model.RegistrationChange, error) {
+	var changes []*model.RegistrationChange
+	query := r.db.Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return changes, nil
+}
+
+func (r registrationChangeRepository) ListByRegistrationID(registrationID string) ([]*model.RegistrationChange, error) {
+	var changes []*model.RegistrationChange
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("created_at DESC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return changes, nil
+}
+
+// Decide stores the decision only if the change is still pending and reports whether this call
+// was the one that decided it, so two admins cannot approve the same change twice.
+func (r registrationChangeRepository) Decide(change model.RegistrationChange) (bool, error) {
+	now := time.Now().UTC()
+	query := r.db.Model(&model.RegistrationChange{}).
+		Where("id = ? AND status = ?", change.Id, string(model.ChangeStatusPending)).
+		Updates(map[string]interface{}{
+			"status":        string(change.Status),
+			"decided_by":    change.DecidedBy,
+			"decided_at":    now,
+			"decision_note": change.DecisionNote,
+			"updated_at":    now,
+		})
+	if query.Error != nil {
+		return false, errs.ErrInternal.Wrap(query.Error)
+	}
+	return query.RowsAffected == 1, nil
+}
+
+func (r registrationChangeRepository) ResetPending(ID string) error {
+	err := r.db.Model(&model.RegistrationChange{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"status":        string(model.ChangeStatusPending),
+			"decided_by":    "",
+			"decided_at":    nil,
+			"decision_note": "",
+			"updated_at":    time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r registrationChangeRepository) SetRefund(ID, refundID string, refundAmount int64) error {
+	err := r.db.Model(&model.RegistrationChange{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"refund_id":     refundID,
+			"refund_amount": refundAmount,
+			"updated_at":    time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var registrationChangeRepositoryInstance *registrationChangeRepository
+var registrationChangeRepositoryOnce sync.Once
+
+func GetRegistrationChangeRepositoryInstance(db *gorm.DB) RegistrationChangeRepository {
+	registrationChangeRepositoryOnce.Do(func() {
+		registrationChangeRepositoryInstance = &registrationChangeRepository{
+			db: db,
+		}
+	})
+	return registrationChangeRepositoryInstance
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type RegistrationGroupRepository interface {
+	Create(group model.RegistrationGroup) (*model.RegistrationGroup, error)
+	Get(ID string) (*model.RegistrationGroup, error)
+	UpdatePaymentStatus(ID, status string) error
+	Remove(ID string) error
+}
+
+type registrationGroupRepository struct {
+	db *gorm.DB
+}
+
+// Create stores the group without its registrations, which are created one by one.
+func (r registrationGroupRepository) Create(group model.RegistrationGroup) (*model.RegistrationGroup, error) {
+	result := r.db.Omit("Registrations").Create(&group)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &group, nil
+}
+
+func (r registrationGroupRepository) Get(ID string) (*model.RegistrationGroup, error) {
+	var group model.RegistrationGroup
+
+	result := r.db.
+		Preload("Registrations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Registrations.RegistrationOption").
+		Preload("Registrations.LineItems").
+		Where("id = ?", ID).First(&group)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrNotFound.Reform("registration group not found")
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &group, nil
+}
+
+func (r registrationGroupRepository) UpdatePaymentStatus(ID, status string) error {
+	err := r.db.Model(&model.RegistrationGroup{}).
+		Where("id = ?", ID).
+		Update("payment_status", status).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r registrationGroupRepository) Remove(ID string) error {
+	err := r.db.Where("id = ?", ID).Delete(&model.RegistrationGroup{}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var registrationGroupRepositoryInstance *registrationGroupRepository
+var registrationGroupRepositoryOnce sync.Once
+
+func GetRegistrationGroupRepositoryInstance(db *gorm.DB) RegistrationGroupRepository {
+	registrationGroupRepositoryOnce.Do(func() {
+		registrationGroupRepositoryInstance = &registrationGroupRepository{
+			db: db,
+		}
+	})
+	return registrationGroupRepositoryInstance
+}
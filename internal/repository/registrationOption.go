@@ -1,7 +1,9 @@
 package repository
 
 import (
+	errs "ashno-onepay/internal/errors"
 	"ashno-onepay/internal/model"
+	"errors"
 	"gorm.io/gorm"
 	"sync"
 )
@@ -9,21 +11,41 @@ import (
 type RegistrationOptionRepository interface {
 	Find(req model.RegistrationOptionFilter) (*model.RegistrationOption, error)
 	ListOption() ([]model.RegistrationOption, error)
+	ListActive() ([]model.RegistrationOption, error)
+	GetByID(ID string) (*model.RegistrationOption, error)
+	Create(option model.RegistrationOption) (*model.RegistrationOption, error)
+	CreateVersion(previousID string, option model.RegistrationOption) (*model.RegistrationOption, error)
+	Activate(ID string) error
+	Deactivate(ID string) error
 }
 
 type registrationOptionRepository struct {
 	db *gorm.DB
 }
 
-func (registrationOptionRepository) ListOption() ([]model.RegistrationOption, error) {
-	//TODO implement me
-	panic("implement me")
+func (r registrationOptionRepository) ListOption() ([]model.RegistrationOption, error) {
+	var options []model.RegistrationOption
+	err := r.db.Order("category ASC, subtype ASC, version DESC").Find(&options).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return options, nil
+}
+
+func (r registrationOptionRepository) ListActive() ([]model.RegistrationOption, error) {
+	var options []model.RegistrationOption
+	err := r.db.Where("active = ?", true).Order("category ASC, subtype ASC").Find(&options).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return options, nil
 }
 
+// Find returns the active option for the category and subtype.
 func (r registrationOptionRepository) Find(req model.RegistrationOptionFilter) (*model.RegistrationOption, error) {
 	var option model.RegistrationOption
 
-	query := r.db.Model(&model.RegistrationOption{})
+	query := r.db.Model(&model.RegistrationOption{}).Where("active = ?", true)
 
 	if req.Category != "" {
 		query = query.Where("category = ?", req.Category)
@@ -32,8 +54,82 @@ func (r registrationOptionRepository) Find(req model.RegistrationOptionFilter) (
 		query = query.Where("subtype = ?", req.Subtype)
 	}
 
-	err := query.First(&option).Error
-	return &option, err
+	err := query.Order("version DESC").First(&option).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrNotFound.Reform("option not found")
+		}
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return &option, nil
+}
+
+func (r registrationOptionRepository) GetByID(ID string) (*model.RegistrationOption, error) {
+	var option model.RegistrationOption
+
+	result := r.db.Where("id = ?", ID).First(&option)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &option, nil
+}
+
+func (r registrationOptionRepository) Create(option model.RegistrationOption) (*model.RegistrationOption, error) {
+	result := r.db.Create(&option)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &option, nil
+}
+
+// CreateVersion stores option as the new version of previousID and deactivates the previous one.
+// The previous row is kept so registrations referencing it keep their price.
+func (r registrationOptionRepository) CreateVersion(previousID string, option model.RegistrationOption) (*model.RegistrationOption, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.RegistrationOption{}).
+			Where("id = ?", previousID).
+			Update("active", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&option).Error
+	})
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return &option, nil
+}
+
+// Activate makes the option the one sold for its category and subtype, deactivating the others.
+func (r registrationOptionRepository) Activate(ID string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var option model.RegistrationOption
+		if err := tx.Where("id = ?", ID).First(&option).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&model.RegistrationOption{}).
+			Where("category = ? AND COALESCE(subtype, '') = ? AND id <> ?", option.Category, option.Subtype, ID).
+			Update("active", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.RegistrationOption{}).Where("id = ?", ID).Update("active", true).Error
+	})
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r registrationOptionRepository) Deactivate(ID string) error {
+	err := r.db.Model(&model.RegistrationOption{}).Where("id = ?", ID).Update("active", false).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
 }
 
 var registrationOptionRepositoryInstance *registrationOptionRepository
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository interface {
+	CreateRefreshToken(token model.RefreshToken) (*model.RefreshToken, error)
+	GetRefreshToken(tokenHash string) (*model.RefreshToken, error)
+	GetRefreshTokenByAccessTokenID(accessTokenID string) (*model.RefreshToken, error)
+	// RevokeRefreshToken revokes a refresh token that is still usable, returning false if it was revoked already.
+	RevokeRefreshToken(ID, replacedBy string, at time.Time) (bool, error)
+	// RevokeUserRefreshTokens revokes the usable refresh tokens of a user and returns them.
+	RevokeUserRefreshTokens(userID string, at time.Time) ([]*model.RefreshToken, error)
+	RevokeAccessToken(tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(tokenID string) (bool, error)
+	// DeleteExpired drops the refresh tokens and revoked access tokens that expired before.
+	DeleteExpired(before time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func (r sessionRepository) CreateRefreshToken(token model.RefreshToken) (*model.RefreshToken, error) {
+	if err := r.db.Create(&token).Error; err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return &token, nil
+}
+
+func (r sessionRepository) GetRefreshToken(tokenHash string) (*model.RefreshToken, error) {
+	return r.getRefreshToken("token_hash = ?", tokenHash)
+}
+
+func (r sessionRepository) GetRefreshTokenByAccessTokenID(accessTokenID string) (*model.RefreshToken, error) {
+	return r.getRefreshToken("access_token_id = ?", accessTokenID)
+}
+
+func (r sessionRepository) getRefreshToken(query string, args ...interface{}) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+
+	result := r.db.Where(query, args...).First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &token, nil
+}
+
+func (r sessionRepository) RevokeRefreshToken(ID, replacedBy string, at time.Time) (bool, error) {
+	result := r.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", ID).
+		Updates(map[string]interface{}{
+			"revoked_at":  at.UTC(),
+			"replaced_by": replacedBy,
+			"updated_at":  time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, errs.ErrInternal.Wrap(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r sessionRepository) RevokeUserRefreshTokens(userID string, at time.Time) ([]*model.RefreshToken, error) {
+	var tokens []*model.RefreshToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, at.UTC()).
+			Find(&tokens).Error
+		if err != nil || len(tokens) == 0 {
+			return err
+		}
+		IDs := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			IDs = append(IDs, token.Id)
+		}
+		return tx.Model(&model.RefreshToken{}).
+			Where("id IN ?", IDs).
+			Updates(map[string]interface{}{
+				"revoked_at": at.UTC(),
+				"updated_at": time.Now().UTC(),
+			}).Error
+	})
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return tokens, nil
+}
+
+func (r sessionRepository) RevokeAccessToken(tokenID string, expiresAt time.Time) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.RevokedToken{TokenID: tokenID, ExpiresAt: expiresAt.UTC()}).Error
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+func (r sessionRepository) IsAccessTokenRevoked(tokenID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	if err != nil {
+		return false, errs.ErrInternal.Wrap(err)
+	}
+	return count > 0, nil
+}
+
+func (r sessionRepository) DeleteExpired(before time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", before.UTC()).Delete(&model.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", before.UTC()).Delete(&model.RevokedToken{}).Error
+	})
+	if err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	return nil
+}
+
+var sessionRepositoryInstance *sessionRepository
+var sessionRepositoryOnce sync.Once
+
+func GetSessionRepositoryInstance(db *gorm.DB) SessionRepository {
+	sessionRepositoryOnce.Do(func() {
+		sessionRepositoryInstance = &sessionRepository{
+			db: db,
+		}
+	})
+	return sessionRepositoryInstance
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WaitlistRepository interface {
+	Create(entry model.WaitlistEntry) (*model.WaitlistEntry, error)
+	Get(ID string) (*model.WaitlistEntry, error)
+	GetActiveByEmail(email string) (*model.WaitlistEntry, error)
+	List(category, status string) ([]*model.WaitlistEntry, error)
+	ListWaiting(category string, limit int) ([]*model.WaitlistEntry, error)
+	ListInvited() ([]*model.WaitlistEntry, error)
+	CountWaiting(category string) (int, error)
+	Position(entry model.WaitlistEntry) (int, error)
+	Invite(ID, registrationID string, invitedAt, expiresAt time.Time) (bool, error)
+	UpdateStatus(ID string, from, to model.WaitlistStatus) (bool, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func (r waitlistRepository) Create(entry model.WaitlistEntry) (*model.WaitlistEntry, error) {
+	result := r.db.Create(&entry)
+	if result.Error != nil {
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &entry, nil
+}
+
+func (r waitlistRepository) Get(ID string) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+
+	result := r.db.Where("id = ?", ID).First(&entry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrNotFound.Reform("waitlist entry not found")
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &entry, nil
+}
+
+// GetActiveByEmail returns the entry of an attendee still waiting or invited, nil when there is none.
+func (r waitlistRepository) GetActiveByEmail(email string) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+
+	result := r.db.Where("LOWER(email) = LOWER(?) AND status IN ?", email,
+		[]string{string(model.WaitlistStatusWaiting), string(model.WaitlistStatusInvited)}).
+		First(&entry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errs.ErrInternal.Wrap(result.Error)
+	}
+	return &entry, nil
+}
+
+// List returns the entries of a category with a status, every entry when they are empty, in queue order.
+func (r waitlistRepository) List(category, status string) ([]*model.WaitlistEntry, error) {
+	var entries []*model.WaitlistEntry
+	query := r.db.Order("created_at ASC")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return entries, nil
+}
+
+// ListWaiting returns the first limit entries waiting for a seat of a category.
+func (r waitlistRepository) ListWaiting(category string, limit int) ([]*model.WaitlistEntry, error) {
+	var entries []*model.WaitlistEntry
+	err := r.db.Where("category = ? AND status = ?", category, string(model.WaitlistStatusWaiting)).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return entries, nil
+}
+
+func (r waitlistRepository) ListInvited() ([]*model.WaitlistEntry, error) {
+	var entries []*model.WaitlistEntry
+	err := r.db.Where("status = ?", string(model.WaitlistStatusInvited)).Order("created_at ASC").Find(&entries).Error
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	return entries, nil
+}
+
+func (r waitlistRepository) CountWaiting(category string) (int, error) {
+	count, err := countWaiting(r.db, category)
+	if err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return count, nil
+}
+
+func countWaiting(db *gorm.DB, category string) (int, error) {
+	var count int64
+	err := db.Model(&model.WaitlistEntry{}).
+		Where("category = ? AND status = ?", category, string(model.WaitlistStatusWaiting)).
+		Count(&count).Error
+	return int(count), err
+}
+
+// Position returns the place of a waiting entry in the queue of its category, 1 being the next invited.
+func (r waitlistRepository) Position(entry model.WaitlistEntry) (int, error) {
+	var count int64
+	err := r.db.Model(&model.WaitlistEntry{}).
+		Where("category = ? AND status = ? AND created_at < ?", entry.Category, string(model.WaitlistStatusWaiting), entry.CreatedAt).
+		Count(&count).Error
+	if err != nil {
+		return 0, errs.ErrInternal.Wrap(err)
+	}
+	return int(count) + 1, nil
+}
+
+// Invite records the registration made for a waiting entry. It reports false when the entry was
+// no longer waiting, i.e. it was invited concurrently.
+func (r waitlistRepository) Invite(ID, registrationID string, invitedAt, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&model.WaitlistEntry{}).
+		Where("id = ? AND status = ?", ID, string(model.WaitlistStatusWaiting)).
+		Updates(map[string]interface{}{
+			"status":          string(model.WaitlistStatusInvited),
+			"registration_id": registrationID,
+			"invited_at":      invitedAt.UTC(),
+			"expires_at":      expiresAt.UTC(),
+			"updated_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, errs.ErrInternal.Wrap(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateStatus moves an entry from one status to another, reporting false when it was not in from.
+func (r waitlistRepository) UpdateStatus(ID string, from, to model.WaitlistStatus) (bool, error) {
+	result := r.db.Model(&model.WaitlistEntry{}).
+		Where("id = ? AND status = ?", ID, string(from)).
+		Updates(map[string]interface{}{
+			"status":     string(to),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, errs.ErrInternal.Wrap(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+var waitlistRepositoryInstance *waitlistRepository
+var waitlistRepositoryOnce sync.Once
+
+func GetWaitlistRepositoryInstance(db *gorm.DB) WaitlistRepository {
+	waitlistRepositoryOnce.Do(func() {
+		waitlistRepositoryInstance = &waitlistRepository{
+			db: db,
+		}
+	})
+	return waitlistRepositoryInstance
+}
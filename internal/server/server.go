@@ -4,6 +4,7 @@ import (
 	"ashno-onepay/internal/config"
 	"ashno-onepay/internal/controller"
 	"ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
 	"ashno-onepay/internal/log"
 	"ashno-onepay/internal/middleware"
 	"ashno-onepay/internal/trace"
@@ -31,6 +32,14 @@ func NewServer(
 	config *config.Config,
 	httpServer *gin.Engine,
 	registrationController *controller.RegistrationController,
+	exchangeRateController *controller.ExchangeRateController,
+	onePayReturnController *controller.OnePayReturnController,
+	periodController *controller.PeriodController,
+	registrationOptionController *controller.RegistrationOptionController,
+	addOnController *controller.AddOnController,
+	promoCodeController *controller.PromoCodeController,
+	manageController *controller.ManageController,
+	adminUserController *controller.AdminUserController,
 	sessionMiddleware gin.HandlerFunc,
 ) *Server {
 	httpServer.Use(func(ctx *gin.Context) {
@@ -66,16 +75,78 @@ func NewServer(
 
 	AddSwagger(httpServer)
 
+	can := middleware.RequirePermission
+
 	//route
 	{
 		route := httpServer.Group("/")
 		{
 			route.POST("/register", registrationController.HandleRegister)
 			route.GET("/register/:registerID/registration-info", registrationController.HandlerGetRegistrationInfo)
+			route.POST("/register/:registerID/payment-url", registrationController.HandleRenewPaymentURL)
+			route.GET("/register/:registerID/offline-payment", registrationController.HandleGetOfflinePayment)
+			route.POST("/register/:registerID/cancellation", registrationController.HandleRequestCancellation)
+			route.POST("/register/:registerID/substitution", registrationController.HandleRequestSubstitution)
+			route.GET("/register/:registerID/changes", registrationController.HandleListRegistrationChanges)
 			route.GET("/onepay/ipn", registrationController.HandlerOnePayIPN)
+			route.GET("/onepay/return", onePayReturnController.HandleOnePayReturn)
+			route.GET("/onepay/result", onePayReturnController.HandleGetPaymentResult)
 			route.GET("/register/option", registrationController.HandlerGetOption)
+			route.GET("/register/options", registrationOptionController.HandleGetCatalogue)
 			route.POST("/register/accompany-persons", registrationController.HandleRegisterAccompanyPersons)
-			route.GET("/register/file", registrationController.HandleGetFile)
+			route.POST("/register/groups", registrationController.HandleRegisterGroup)
+			route.GET("/register/groups/:groupID", registrationController.HandleGetGroup)
+			route.POST("/register/groups/:groupID/payment-url", registrationController.HandleRenewGroupPaymentURL)
+			route.POST("/register/waitlist", registrationController.HandleJoinWaitlist)
+			route.GET("/register/waitlist/:entryID", registrationController.HandleGetWaitlistEntry)
+			route.POST("/register/manage-link", manageController.HandleRequestManageLink)
+			route.GET("/register/manage", manageController.HandleGetManagedRegistration)
+			route.PATCH("/register/manage", manageController.HandleUpdateManagedRegistration)
+			route.POST("/auth/login", adminUserController.HandleLogin)
+			route.POST("/auth/refresh", adminUserController.HandleRefresh)
+			route.POST("/auth/logout", sessionMiddleware, adminUserController.HandleLogout)
+			route.POST("/auth/logout-everywhere", sessionMiddleware, adminUserController.HandleLogoutEverywhere)
+			route.GET("/auth/me", sessionMiddleware, adminUserController.HandleGetCurrentUser)
+			// the export holds every attendee's personal data
+			route.GET("/register/file", sessionMiddleware, can(jwt.PermissionRegistrationsExport), registrationController.HandleGetFile)
+		}
+		admin := httpServer.Group("/admin", sessionMiddleware)
+		{
+			admin.GET("/registrations/:registerID/payment-transactions", can(jwt.PermissionPaymentsRead), registrationController.HandleGetPaymentTransactions)
+			admin.POST("/registrations/:registerID/refunds", can(jwt.PermissionPaymentsManage), registrationController.HandleRefund)
+			admin.POST("/registrations/:registerID/offline-payment/confirm", can(jwt.PermissionPaymentsManage), registrationController.HandleConfirmOfflinePayment)
+			admin.POST("/registrations/import", can(jwt.PermissionRegistrationsEdit), registrationController.HandleImportRegistrations)
+			admin.GET("/registrations/:registerID/audit-log", can(jwt.PermissionRegistrationsRead), registrationController.HandleGetAuditLog)
+			admin.POST("/check-ins", can(jwt.PermissionRegistrationsCheckIn), registrationController.HandleCheckIn)
+			admin.GET("/registration-changes", can(jwt.PermissionRegistrationsRead), registrationController.HandleListChanges)
+			admin.POST("/registration-changes/:changeID/approve", can(jwt.PermissionRegistrationsEdit), registrationController.HandleApproveChange)
+			admin.POST("/registration-changes/:changeID/reject", can(jwt.PermissionRegistrationsEdit), registrationController.HandleRejectChange)
+			admin.GET("/waitlist", can(jwt.PermissionRegistrationsRead), registrationController.HandleListWaitlist)
+			admin.POST("/waitlist/invitations", can(jwt.PermissionRegistrationsEdit), registrationController.HandleInviteFromWaitlist)
+			admin.GET("/exchange-rate", can(jwt.PermissionSettingsRead), exchangeRateController.HandleGetExchangeRate)
+			admin.PUT("/exchange-rate", can(jwt.PermissionSettingsManage), exchangeRateController.HandleSetExchangeRate)
+			admin.DELETE("/exchange-rate", can(jwt.PermissionSettingsManage), exchangeRateController.HandleClearExchangeRate)
+			admin.GET("/registration-periods", can(jwt.PermissionSettingsRead), periodController.HandleListPeriods)
+			admin.PUT("/registration-periods", can(jwt.PermissionSettingsManage), periodController.HandleReplacePeriods)
+			admin.GET("/registration-options", can(jwt.PermissionSettingsRead), registrationOptionController.HandleListOptions)
+			admin.POST("/registration-options", can(jwt.PermissionSettingsManage), registrationOptionController.HandleCreateOption)
+			admin.PUT("/registration-options/:optionID", can(jwt.PermissionSettingsManage), registrationOptionController.HandleUpdateOption)
+			admin.POST("/registration-options/:optionID/activate", can(jwt.PermissionSettingsManage), registrationOptionController.HandleActivateOption)
+			admin.POST("/registration-options/:optionID/deactivate", can(jwt.PermissionSettingsManage), registrationOptionController.HandleDeactivateOption)
+			admin.GET("/registration-capacities", can(jwt.PermissionSettingsRead), registrationOptionController.HandleListCapacities)
+			admin.PUT("/registration-capacities", can(jwt.PermissionSettingsManage), registrationOptionController.HandleReplaceCapacities)
+			admin.GET("/add-ons", can(jwt.PermissionSettingsRead), addOnController.HandleListAddOns)
+			admin.POST("/add-ons", can(jwt.PermissionSettingsManage), addOnController.HandleCreateAddOn)
+			admin.PUT("/add-ons/:addOnID", can(jwt.PermissionSettingsManage), addOnController.HandleUpdateAddOn)
+			admin.PUT("/add-ons/:addOnID/prices", can(jwt.PermissionSettingsManage), addOnController.HandleReplacePrices)
+			admin.GET("/promo-codes", can(jwt.PermissionSettingsRead), promoCodeController.HandleListPromoCodes)
+			admin.POST("/promo-codes", can(jwt.PermissionSettingsManage), promoCodeController.HandleCreatePromoCode)
+			admin.PUT("/promo-codes/:promoCodeID", can(jwt.PermissionSettingsManage), promoCodeController.HandleUpdatePromoCode)
+			admin.GET("/roles", can(jwt.PermissionUsersManage), adminUserController.HandleListRoles)
+			admin.GET("/users", can(jwt.PermissionUsersManage), adminUserController.HandleListUsers)
+			admin.POST("/users", can(jwt.PermissionUsersManage), adminUserController.HandleCreateUser)
+			admin.PUT("/users/:userID", can(jwt.PermissionUsersManage), adminUserController.HandleUpdateUser)
+			admin.PUT("/users/:userID/role", can(jwt.PermissionUsersManage), adminUserController.HandleAssignRole)
 		}
 	}
 
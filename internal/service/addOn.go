@@ -0,0 +1,253 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
+	"sync"
+	"time"
+)
+
+type AddOnService interface {
+	ListAddOns() ([]*model.AddOn, error)
+	CreateAddOn(request dto.CreateAddOnRequest) (*model.AddOn, error)
+	UpdateAddOn(ID string, request dto.UpdateAddOnRequest) (*model.AddOn, error)
+	ReplacePrices(ID string, request dto.ReplaceAddOnPricesRequest) (*model.AddOn, error)
+	// GetCatalogue lists the active add-ons with their price during the period subtype.
+	GetCatalogue(subtype string) ([]dto.CatalogueAddOn, error)
+	// PriceLineItems prices the ordered add-ons for the period subtype without reserving them.
+	PriceLineItems(registrationID, transactionID, subtype string, requests []dto.AddOnRequest) (model.LineItemList, error)
+	// Reserve stores priced line items, failing with ErrSoldOut when an add-on is out of capacity.
+	Reserve(items model.LineItemList) error
+	// Reprice updates the pending items of a registration, and the ones its failed payment gave back,
+	// to the prices of the period subtype and reserves them again, failing with ErrSoldOut when an
+	// add-on is out of capacity.
+	Reprice(items model.LineItemList, subtype string) (model.LineItemList, error)
+	// MarkLineItems settles the pending items of a registration ordered with transactionID.
+	MarkLineItems(registrationID, transactionID string, status model.LineItemStatus) error
+	// CancelLineItems cancels the items of a cancelled registration.
+	CancelLineItems(registrationID string) error
+	GalaDinnerPrice(subtype string) (*model.AddOnPrice, error)
+}
+
+type addOnService struct {
+	addOnRepo repository.AddOnRepository
+	config    *config.Config
+}
+
+func (s addOnService) ListAddOns() ([]*model.AddOn, error) {
+	return s.addOnRepo.List()
+}
+
+func (s addOnService) CreateAddOn(request dto.CreateAddOnRequest) (*model.AddOn, error) {
+	return s.addOnRepo.Create(model.AddOn{
+		Code:        request.Code,
+		Name:        request.Name,
+		Description: request.Description,
+		Capacity:    request.Capacity,
+		Active:      true,
+	})
+}
+
+func (s addOnService) UpdateAddOn(ID string, request dto.UpdateAddOnRequest) (*model.AddOn, error) {
+	addOn, err := s.getAddOn(ID)
+	if err != nil {
+		return nil, err
+	}
+	addOn.Name = request.Name
+	addOn.Description = request.Description
+	addOn.Capacity = request.Capacity
+	addOn.Active = request.Active
+	if err := s.addOnRepo.UpdateDetails(*addOn); err != nil {
+		return nil, err
+	}
+	return s.getAddOn(ID)
+}
+
+// ReplacePrices sets the prices of an add-on. Line items already ordered keep their price.
+func (s addOnService) ReplacePrices(ID string, request dto.ReplaceAddOnPricesRequest) (*model.AddOn, error) {
+	if _, err := s.getAddOn(ID); err != nil {
+		return nil, err
+	}
+	prices := make([]model.AddOnPrice, 0, len(request.Prices))
+	subtypes := map[string]bool{}
+	for _, price := range request.Prices {
+		if subtypes[price.Subtype] {
+			return nil, errs.ErrInvalidArgument.Reform("price for period %q is given twice", price.Subtype)
+		}
+		subtypes[price.Subtype] = true
+		if !model.IsWholeUSD(price.FeeUSD) {
+			return nil, errs.ErrInvalidArgument.Reform("fee_usd must be whole dollars")
+		}
+		prices = append(prices, model.AddOnPrice{
+			AddOnID: ID,
+			Subtype: price.Subtype,
+			FeeUSD:  price.FeeUSD,
+			FeeVND:  price.FeeVND,
+		})
+	}
+	if err := s.addOnRepo.ReplacePrices(ID, prices); err != nil {
+		return nil, err
+	}
+	return s.getAddOn(ID)
+}
+
+func (s addOnService) GetCatalogue(subtype string) ([]dto.CatalogueAddOn, error) {
+	addOns, err := s.addOnRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	catalogue := []dto.CatalogueAddOn{}
+	for _, addOn := range addOns {
+		price := addOn.PriceFor(subtype)
+		if !addOn.Active || price == nil {
+			continue
+		}
+		remaining := -1
+		if addOn.Capacity > 0 {
+			reserved, err := s.addOnRepo.CountReserved(addOn.Id, s.pendingSince())
+			if err != nil {
+				return nil, err
+			}
+			remaining = max(addOn.Capacity-reserved, 0)
+		}
+		catalogue = append(catalogue, dto.CatalogueAddOn{
+			Code:        addOn.Code,
+			Name:        addOn.Name,
+			Description: addOn.Description,
+			FeeUSD:      price.FeeUSD,
+			FeeVND:      price.FeeVND,
+			Capacity:    addOn.Capacity,
+			Remaining:   remaining,
+		})
+	}
+	return catalogue, nil
+}
+
+func (s addOnService) PriceLineItems(registrationID, transactionID, subtype string, requests []dto.AddOnRequest) (model.LineItemList, error) {
+	items := model.LineItemList{}
+	for _, request := range requests {
+		if request.Quantity <= 0 {
+			continue
+		}
+		addOn, err := s.addOnRepo.GetByCode(request.Code)
+		if err != nil {
+			return nil, err
+		}
+		if addOn == nil || !addOn.Active {
+			return nil, errs.ErrNotFound.Reform("add-on %s not found", request.Code)
+		}
+		price := addOn.PriceFor(subtype)
+		if price == nil {
+			return nil, errs.ErrNotFound.Reform("add-on %s is not sold in this period", request.Code)
+		}
+		items = append(items, model.RegistrationLineItem{
+			RegistrationID:   registrationID,
+			AddOnID:          addOn.Id,
+			Code:             addOn.Code,
+			Name:             addOn.Name,
+			TransactionID:    transactionID,
+			AccompanyPersons: request.AccompanyPersons,
+			Quantity:         request.Quantity,
+			UnitFeeUSD:       price.FeeUSD,
+			UnitFeeVND:       price.FeeVND,
+			Status:           string(model.LineItemStatusPending),
+		})
+	}
+	return items, nil
+}
+
+func (s addOnService) Reserve(items model.LineItemList) error {
+	return s.addOnRepo.Reserve(items, s.pendingSince())
+}
+
+func (s addOnService) Reprice(items model.LineItemList, subtype string) (model.LineItemList, error) {
+	repriced := make(model.LineItemList, len(items))
+	copy(repriced, items)
+	for i := range repriced {
+		// the items of a failed registration payment are ordered again, unlike the accompany
+		// person purchases that failed on their own
+		failed := repriced[i].Status == string(model.LineItemStatusFail) && repriced[i].TransactionID == ""
+		if repriced[i].Status != string(model.LineItemStatusPending) && !failed {
+			continue
+		}
+		addOn, err := s.addOnRepo.GetByID(repriced[i].AddOnID)
+		if err != nil {
+			return nil, err
+		}
+		if addOn == nil {
+			continue
+		}
+		price := addOn.PriceFor(subtype)
+		if price == nil {
+			return nil, errs.ErrNotFound.Reform("add-on %s is not sold in this period", addOn.Code)
+		}
+		err = s.addOnRepo.RenewLineItem(repriced[i].Id, price.FeeUSD, price.FeeVND, s.pendingSince())
+		if err != nil {
+			return nil, err
+		}
+		repriced[i].UnitFeeUSD = price.FeeUSD
+		repriced[i].UnitFeeVND = price.FeeVND
+		repriced[i].Status = string(model.LineItemStatusPending)
+	}
+	return repriced, nil
+}
+
+func (s addOnService) MarkLineItems(registrationID, transactionID string, status model.LineItemStatus) error {
+	return s.addOnRepo.UpdateLineItemsStatus(registrationID, transactionID, model.LineItemStatusPending, status)
+}
+
+func (s addOnService) CancelLineItems(registrationID string) error {
+	return s.addOnRepo.CancelLineItems(registrationID)
+}
+
+func (s addOnService) GalaDinnerPrice(subtype string) (*model.AddOnPrice, error) {
+	addOn, err := s.addOnRepo.GetByCode(model.AddOnCodeGalaDinner)
+	if err != nil {
+		return nil, err
+	}
+	if addOn == nil || !addOn.Active {
+		return nil, errs.ErrNotFound.Reform("add-on %s not found", model.AddOnCodeGalaDinner)
+	}
+	price := addOn.PriceFor(subtype)
+	if price == nil {
+		return nil, errs.ErrNotFound.Reform("add-on %s is not sold in this period", model.AddOnCodeGalaDinner)
+	}
+	return price, nil
+}
+
+// pendingSince is when the oldest pending line item still holding capacity was ordered:
+// one whose payment link has not expired yet.
+func (s addOnService) pendingSince() time.Time {
+	return time.Now().Add(-s.config.Payment.GetLinkTTL() - s.config.Payment.GetExpiryGrace())
+}
+
+func (s addOnService) getAddOn(ID string) (*model.AddOn, error) {
+	addOn, err := s.addOnRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if addOn == nil {
+		return nil, errs.ErrNotFound.Reform("add-on not found")
+	}
+	return addOn, nil
+}
+
+var addOnServiceInstance AddOnService
+var addOnServiceOnce sync.Once
+
+func GetAddOnServiceInstance(addOnRepo repository.AddOnRepository, config *config.Config) AddOnService {
+	addOnServiceOnce.Do(func() {
+		addOnServiceInstance = NewAddOnService(addOnRepo, config)
+	})
+	return addOnServiceInstance
+}
+
+func NewAddOnService(addOnRepo repository.AddOnRepository, config *config.Config) AddOnService {
+	return &addOnService{
+		addOnRepo: addOnRepo,
+		config:    config,
+	}
+}
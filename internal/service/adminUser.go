@@ -0,0 +1,212 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AdminUserService interface {
+	// Login starts a session for the active admin user with the email and password.
+	Login(email, password string) (*dto.LoginResponse, error)
+	// Refresh renews a session, replacing the refresh token with a new one.
+	Refresh(refreshToken string) (*dto.LoginResponse, error)
+	// Logout ends the session the access token belongs to.
+	Logout(claims *jwt.UserClaims) error
+	// LogoutEverywhere ends every session of an admin user.
+	LogoutEverywhere(userID string) error
+	IsRevoked(tokenID string) (bool, error)
+	GetUser(ID string) (*model.AdminUser, error)
+	ListUsers() ([]*model.AdminUser, error)
+	CreateUser(request dto.CreateAdminUserRequest) (*model.AdminUser, error)
+	UpdateUser(ID, operatorID string, request dto.UpdateAdminUserRequest) (*model.AdminUser, error)
+	AssignRole(ID, operatorID string, role jwt.Role) (*model.AdminUser, error)
+	// Bootstrap creates the configured admin when there are no admin users yet.
+	Bootstrap() error
+}
+
+type adminUserService struct {
+	adminUserRepo repository.AdminUserRepository
+	sessionRepo   repository.SessionRepository
+	issuer        jwt.Issuer
+	config        *config.Config
+}
+
+// dummyHash is compared against when the email is unknown so that both failures take as long.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("ashno-onepay"), bcrypt.DefaultCost)
+
+// authenticate returns the active admin user with the email and password.
+func (s adminUserService) authenticate(email, password string) (*model.AdminUser, error) {
+	user, err := s.adminUserRepo.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, errs.ErrInvalidPassword.Reform("email or password is incorrect")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, errs.ErrInvalidPassword.Reform("email or password is incorrect")
+	}
+	if !user.Active {
+		return nil, errs.ErrForbidden.Reform("admin user is disabled")
+	}
+	now := time.Now().UTC()
+	if err := s.adminUserRepo.UpdateLastLogin(user.Id, now); err != nil {
+		log.Printf("failed to record login of admin user %s: %v", user.Id, err)
+	}
+	user.LastLoginAt = &now
+	return user, nil
+}
+
+func (s adminUserService) GetUser(ID string) (*model.AdminUser, error) {
+	user, err := s.adminUserRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errs.ErrNotFound.Reform("admin user not found")
+	}
+	return user, nil
+}
+
+func (s adminUserService) ListUsers() ([]*model.AdminUser, error) {
+	return s.adminUserRepo.List()
+}
+
+func (s adminUserService) CreateUser(request dto.CreateAdminUserRequest) (*model.AdminUser, error) {
+	if !request.Role.IsValid() {
+		return nil, errs.ErrInvalidArgument.Reform("role %s is not valid", request.Role)
+	}
+	hash, err := hashPassword(request.Password)
+	if err != nil {
+		return nil, err
+	}
+	return s.adminUserRepo.Create(model.AdminUser{
+		Email:        model.NormalizeEmail(request.Email),
+		Name:         strings.TrimSpace(request.Name),
+		PasswordHash: hash,
+		Role:         string(request.Role),
+		Active:       true,
+	})
+}
+
+// UpdateUser changes an admin user. Admins cannot disable or demote themselves, so there is
+// always someone left to manage the others. A new password, role or disabling the user ends their
+// sessions, so they sign in again with the new ones.
+func (s adminUserService) UpdateUser(ID, operatorID string, request dto.UpdateAdminUserRequest) (*model.AdminUser, error) {
+	user, err := s.GetUser(ID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Name != nil {
+		user.Name = strings.TrimSpace(*request.Name)
+	}
+	endSessions := false
+	if request.Password != nil {
+		endSessions = true
+		if user.PasswordHash, err = hashPassword(*request.Password); err != nil {
+			return nil, err
+		}
+	}
+	if request.Role != nil {
+		if !request.Role.IsValid() {
+			return nil, errs.ErrInvalidArgument.Reform("role %s is not valid", *request.Role)
+		}
+		if ID == operatorID && *request.Role != jwt.Role(user.Role) {
+			return nil, errs.ErrBadRequest.Reform("you cannot change your own role")
+		}
+		endSessions = endSessions || jwt.Role(user.Role) != *request.Role
+		user.Role = string(*request.Role)
+	}
+	if request.Active != nil {
+		if ID == operatorID && !*request.Active {
+			return nil, errs.ErrBadRequest.Reform("you cannot disable yourself")
+		}
+		endSessions = endSessions || !*request.Active
+		user.Active = *request.Active
+	}
+	if err := s.adminUserRepo.Update(*user); err != nil {
+		return nil, err
+	}
+	if endSessions {
+		if err := s.LogoutEverywhere(ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetUser(ID)
+}
+
+// AssignRole gives an admin user another role, ending their sessions so it takes effect.
+func (s adminUserService) AssignRole(ID, operatorID string, role jwt.Role) (*model.AdminUser, error) {
+	return s.UpdateUser(ID, operatorID, dto.UpdateAdminUserRequest{Role: &role})
+}
+
+func (s adminUserService) Bootstrap() error {
+	if s.config.Admin.BootstrapEmail == "" {
+		return nil
+	}
+	count, err := s.adminUserRepo.Count()
+	if err != nil || count > 0 {
+		return err
+	}
+	if len(s.config.Admin.BootstrapPassword) < 10 {
+		return errs.ErrInvalidArgument.Reform("bootstrap admin password must be at least 10 characters")
+	}
+	user, err := s.CreateUser(dto.CreateAdminUserRequest{
+		Email:    s.config.Admin.BootstrapEmail,
+		Password: s.config.Admin.BootstrapPassword,
+		Role:     jwt.AdminRole,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("created bootstrap admin user %s", user.Email)
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errs.ErrInvalidArgument.Wrap(err).Reform("password cannot be used")
+	}
+	return string(hash), nil
+}
+
+var adminUserServiceInstance AdminUserService
+var adminUserServiceOnce sync.Once
+
+func GetAdminUserServiceInstance(
+	adminUserRepo repository.AdminUserRepository,
+	sessionRepo repository.SessionRepository,
+	issuer jwt.Issuer,
+	config *config.Config,
+) AdminUserService {
+	adminUserServiceOnce.Do(func() {
+		adminUserServiceInstance = NewAdminUserService(adminUserRepo, sessionRepo, issuer, config)
+	})
+	return adminUserServiceInstance
+}
+
+func NewAdminUserService(
+	adminUserRepo repository.AdminUserRepository,
+	sessionRepo repository.SessionRepository,
+	issuer jwt.Issuer,
+	config *config.Config,
+) AdminUserService {
+	return &adminUserService{
+		adminUserRepo: adminUserRepo,
+		sessionRepo:   sessionRepo,
+		issuer:        issuer,
+		config:        config,
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"strings"
+	"time"
+)
+
+// CheckIn admits the attendee of a scanned ticket. Only paid registrations whose ticket is current
+// are admitted; scanning a ticket twice is answered with when it was first scanned.
+func (r registrationService) CheckIn(request dto.CheckInRequest, operatorID string) (*dto.CheckInResponse, error) {
+	reg, err := r.registrationRepo.GetRegistration(strings.TrimSpace(request.RegistrationID))
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil || !reg.HasTicket(request.Ticket) {
+		return nil, errs.ErrNotFound.Reform("ticket is not valid")
+	}
+	switch model.PaymentStatus(reg.PaymentStatus) {
+	case model.PaymentStatusDone, model.PaymentStatusPartiallyRefunded:
+	default:
+		return nil, errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+
+	now := time.Now().UTC()
+	checkedIn, err := r.registrationRepo.CheckIn(reg.Id, operatorID, now)
+	if err != nil {
+		return nil, err
+	}
+	if checkedIn {
+		reg.CheckedInAt = &now
+		r.audit(reg.Id, model.AuditActionCheckedIn, operatorID, "")
+	} else if reg.CheckedInAt == nil {
+		// checked in by someone else since it was read
+		if reg, err = r.registrationRepo.GetRegistration(reg.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.Join(strings.Fields(strings.Join([]string{reg.FirstName, reg.MiddleName, reg.LastName}, " ")), " ")
+	return &dto.CheckInResponse{
+		RegistrationID:       reg.Id,
+		Name:                 name,
+		DoctorateDegree:      reg.DoctorateDegree,
+		RegistrationCategory: reg.RegistrationCategory,
+		Institution:          reg.Institution,
+		AttendsGalaDinner:    reg.AttendsGalaDinner(),
+		AccompanyPersons:     len(reg.AccompanyPersons),
+		AlreadyCheckedIn:     !checkedIn,
+		CheckedInAt:          reg.CheckedInAt,
+	}, nil
+}
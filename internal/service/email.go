@@ -110,9 +110,10 @@ func AddInlineAttachment(message *mail.SGMailV3, filePath string, cid string, co
 	return nil
 }
 
-// SendRegistrationSuccessEmail sends email using HTML templates with CID-referenced images
+// SendRegistrationSuccessEmail sends email using HTML templates with CID-referenced images.
+// ticketPath is what the QR code points to under OnePay.ReturnURL, see Registration.TicketPath.
 func SendRegistrationSuccessEmail(
-	toEmail, toName, registerID, language string,
+	toEmail, toName, ticketPath, language string,
 	templateData TemplateData,
 	config *config.Config,
 ) error {
@@ -154,7 +155,7 @@ func SendRegistrationSuccessEmail(
 	message := mail.NewSingleEmail(from, subject, to, "", htmlContent)
 
 	// Generate and add QR code as inline attachment
-	qrURL := fmt.Sprintf("%s/%s", config.OnePay.ReturnURL, registerID)
+	qrURL := fmt.Sprintf("%s/%s", config.OnePay.ReturnURL, ticketPath)
 	png, err := qrcode.Encode(qrURL, qrcode.Medium, 256)
 	if err != nil {
 		return fmt.Errorf("failed to generate QR code: %w", err)
@@ -232,7 +233,7 @@ func SendPaymentSuccessEmailWithQR(
 
 // SendRegistrationEmailWithTemplate is a convenience function for easy usage
 func SendRegistrationEmailWithTemplate(
-	toEmail, toName, registerID, language string,
+	toEmail, toName, ticketPath, language string,
 	fullName, phoneNumber, registrationFee string,
 	config *config.Config,
 ) error {
@@ -247,9 +248,30 @@ func SendRegistrationEmailWithTemplate(
 	return SendRegistrationSuccessEmail(
 		toEmail,
 		toName,
-		registerID,
+		ticketPath,
 		language,
 		templateData,
 		config,
 	)
 }
+
+// SendNoticeEmail sends a short plain notice, e.g. about a cancellation or substitution request.
+func SendNoticeEmail(toEmail, toName, subject, body string, config *config.Config) error {
+	from := mail.NewEmail(config.SendGrip.SenderName, config.SendGrip.SenderEmail)
+	to := mail.NewEmail(toName, toEmail)
+
+	htmlContent := fmt.Sprintf(`
+		Hi %s,<br><br>
+		%s<br><br>
+		Thanks,<br>
+		%s
+	`, template.HTMLEscapeString(toName), template.HTMLEscapeString(body), template.HTMLEscapeString(config.Event.Name))
+
+	message := mail.NewSingleEmail(from, subject, to, body, htmlContent)
+	client := sendgrid.NewSendClient(config.SendGrip.ApiKey)
+	_, err := client.Send(message)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/vietqr"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// GetOfflinePayment tells how to pay a registration made for bank transfer or cash.
+func (r registrationService) GetOfflinePayment(registrationID string) (*dto.OfflinePayment, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if model.PaymentMethod(reg.PaymentMethod).IsOnePay() {
+		return nil, errs.ErrBadRequest.Reform("registration is paid through OnePay")
+	}
+	amount, currency := amountDue(reg)
+	payment := &dto.OfflinePayment{
+		PaymentMethod: reg.PaymentMethod,
+		PaymentStatus: reg.PaymentStatus,
+		Amount:        amount,
+		Currency:      currency,
+	}
+	if reg.PaymentMethod == string(model.PaymentMethodBankTransfer) {
+		if payment.BankTransfer, err = r.bankTransfer(reg); err != nil {
+			return nil, err
+		}
+	}
+	return payment, nil
+}
+
+// GetBankTransfer returns the VietQR transfer that pays a registration, nil when it cannot be paid
+// by bank transfer: it is not charged in VND, is paid in cash or by its sponsor group, or is paid already.
+func (r registrationService) GetBankTransfer(registrationID string) (*dto.BankTransfer, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !r.acceptsBankTransfer(reg) || reg.PaymentStatus == string(model.PaymentStatusDone) {
+		return nil, nil
+	}
+	return r.bankTransfer(reg)
+}
+
+func (r registrationService) bankTransfer(reg *model.Registration) (*dto.BankTransfer, error) {
+	bank := r.config.BankTransfer
+	_, amount := reg.Fee()
+	payload, err := vietqr.Payload(vietqr.Transfer{
+		BankBIN:       bank.BankBIN,
+		AccountNumber: bank.AccountNumber,
+		Amount:        amount,
+		Memo:          reg.TransferReference,
+	})
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err).Reform("bank transfer is not available")
+	}
+	png, err := qrcode.Encode(payload, qrcode.Medium, 256)
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err).Reform("failed to generate QR code")
+	}
+	return &dto.BankTransfer{
+		BankName:          bank.BankName,
+		BankBIN:           bank.BankBIN,
+		AccountNumber:     bank.AccountNumber,
+		AccountName:       bank.AccountName,
+		Amount:            amount,
+		TransferReference: reg.TransferReference,
+		VietQRPayload:     payload,
+		VietQRImage:       "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// acceptsBankTransfer reports whether a registration may be paid by bank transfer, either as its
+// payment method or instead of its OnePay payment URL.
+func (r registrationService) acceptsBankTransfer(reg *model.Registration) bool {
+	if !r.config.BankTransfer.Enabled() || reg.TransferReference == "" || reg.GroupID != "" {
+		return false
+	}
+	method := model.PaymentMethod(reg.PaymentMethod)
+	return method == model.PaymentMethodBankTransfer || method.IsOnePay()
+}
+
+// ConfirmOfflinePayment records a bank transfer or cash payment an admin received and marks the
+// registration paid, sending the same confirmation email as a OnePay payment. A transfer may also
+// pay a registration made for OnePay, which then becomes a bank transfer one.
+func (r registrationService) ConfirmOfflinePayment(
+	registrationID string, request dto.ConfirmOfflinePaymentRequest, operator, clientIP string,
+) (*model.Registration, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if reg.GroupID != "" {
+		return nil, errs.ErrBadRequest.Reform("registration is paid by its sponsor group")
+	}
+	// a registrant with a OnePay payment URL may have paid by bank transfer instead
+	if model.PaymentMethod(reg.PaymentMethod).IsOnePay() && !r.acceptsBankTransfer(reg) {
+		return nil, errs.ErrBadRequest.Reform("registration is paid through OnePay")
+	}
+	if reg.PaymentStatus != string(model.PaymentStatusPending) && reg.PaymentStatus != string(model.PaymentStatusFail) {
+		return nil, errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	// the OnePay links sent before can no longer pay it; a result arriving for one of them is
+	// rejected as expired rather than paying the registration twice
+	if err := r.paymentTransactionRepo.ExpirePending(reg.Id, "paid outside OnePay"); err != nil {
+		return nil, err
+	}
+	// an IPN may have paid it before its attempt was closed
+	if reg, err = r.registrationRepo.GetRegistration(registrationID); err != nil {
+		return nil, err
+	}
+	if reg.PaymentStatus != string(model.PaymentStatusPending) && reg.PaymentStatus != string(model.PaymentStatusFail) {
+		return nil, errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	due, currency := amountDue(reg)
+	amount := request.Amount
+	if amount == 0 {
+		amount = due
+	}
+	if amount < due {
+		return nil, errs.ErrInvalidArgument.Reform("%d %s received, %d %s is due", amount, currency, due, currency)
+	}
+	if model.PaymentMethod(reg.PaymentMethod).IsOnePay() {
+		reg.PaymentMethod = string(model.PaymentMethodBankTransfer)
+		if err := r.registrationRepo.UpdatePaymentMethod(reg.Id, reg.PaymentMethod); err != nil {
+			return nil, err
+		}
+	}
+
+	message := fmt.Sprintf("%s received, confirmed by %s", strings.ReplaceAll(reg.PaymentMethod, "_", " "), operator)
+	if reg.PaymentMethod == string(model.PaymentMethodBankTransfer) {
+		message += ", reference " + reg.TransferReference
+	}
+	if request.Note != "" {
+		message += ": " + request.Note
+	}
+	if err := r.settleOutsideOnePay(reg, amount, currency, message, clientIP); err != nil {
+		return nil, err
+	}
+	return r.registrationRepo.GetRegistration(registrationID)
+}
+
+// amountDue is what a registration costs in the currency its registrant is charged in.
+func amountDue(reg *model.Registration) (int64, string) {
+	feeUSD, feeVND := reg.Fee()
+	if reg.Nationality == model.NationalityVietNam {
+		return feeVND, CurrencyVND
+	}
+	// USD prices are whole dollars; fees with cents from before then are not charged short
+	return int64(math.Ceil(feeUSD)), CurrencyUSD
+}
+
+// transferReference is the memo of a bank transfer paying a registration: the configured prefix
+// and as much of the registration ID as the memo holds.
+func (r registrationService) transferReference(registrationID string) string {
+	reference := r.config.BankTransfer.ReferencePrefix + strings.ToUpper(strings.ReplaceAll(registrationID, "-", ""))
+	return reference[:min(len(reference), vietqr.MaxMemoLength)]
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+type PeriodService interface {
+	// CurrentPeriod returns the active registration period, or ErrRegistrationClosed when there is none.
+	CurrentPeriod() (*model.Period, error)
+	ListPeriods() ([]*model.Period, error)
+	ReplacePeriods(request dto.ReplacePeriodsRequest) ([]*model.Period, error)
+}
+
+type periodService struct {
+	periodRepo repository.PeriodRepository
+}
+
+func (s periodService) CurrentPeriod() (*model.Period, error) {
+	period, err := s.periodRepo.FindActive(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, errs.ErrRegistrationClosed
+	}
+	return period, nil
+}
+
+func (s periodService) ListPeriods() ([]*model.Period, error) {
+	return s.periodRepo.List()
+}
+
+// ReplacePeriods replaces the schedule. Periods must follow each other without overlaps or
+// gaps, so that exactly one is active at any time between the first start and the last end.
+func (s periodService) ReplacePeriods(request dto.ReplacePeriodsRequest) ([]*model.Period, error) {
+	periods := make([]model.Period, 0, len(request.Periods))
+	names := map[string]bool{}
+	for _, p := range request.Periods {
+		location, err := time.LoadLocation(p.TimeZone)
+		if err != nil {
+			return nil, errs.ErrInvalidArgument.Reform("period %s: invalid time zone %s", p.Name, p.TimeZone)
+		}
+		startAt, err := time.ParseInLocation(dto.PeriodLayout, p.StartAt, location)
+		if err != nil {
+			return nil, errs.ErrInvalidArgument.Reform("period %s: invalid start_at %s", p.Name, p.StartAt)
+		}
+		endAt, err := time.ParseInLocation(dto.PeriodLayout, p.EndAt, location)
+		if err != nil {
+			return nil, errs.ErrInvalidArgument.Reform("period %s: invalid end_at %s", p.Name, p.EndAt)
+		}
+		if !startAt.Before(endAt) {
+			return nil, errs.ErrInvalidArgument.Reform("period %s must start before it ends", p.Name)
+		}
+		if names[p.Name] {
+			return nil, errs.ErrInvalidArgument.Reform("period %s is given twice", p.Name)
+		}
+		names[p.Name] = true
+		periods = append(periods, model.Period{
+			Name:     p.Name,
+			StartAt:  startAt.UTC(),
+			EndAt:    endAt.UTC(),
+			TimeZone: p.TimeZone,
+		})
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].StartAt.Before(periods[j].StartAt)
+	})
+	for i := 1; i < len(periods); i++ {
+		previous, next := periods[i-1], periods[i]
+		if next.StartAt.Before(previous.EndAt) {
+			return nil, errs.ErrInvalidArgument.Reform("periods %s and %s overlap", previous.Name, next.Name)
+		}
+		if next.StartAt.After(previous.EndAt) {
+			return nil, errs.ErrInvalidArgument.Reform("gap between periods %s and %s", previous.Name, next.Name)
+		}
+	}
+	return s.periodRepo.ReplaceAll(periods)
+}
+
+var periodServiceInstance PeriodService
+var periodServiceOnce sync.Once
+
+func GetPeriodServiceInstance(periodRepo repository.PeriodRepository) PeriodService {
+	periodServiceOnce.Do(func() {
+		periodServiceInstance = NewPeriodService(periodRepo)
+	})
+	return periodServiceInstance
+}
+
+func NewPeriodService(periodRepo repository.PeriodRepository) PeriodService {
+	return &periodService{
+		periodRepo: periodRepo,
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PromoCodeService interface {
+	ListPromoCodes() ([]*model.PromoCode, error)
+	CreatePromoCode(request dto.CreatePromoCodeRequest) (*model.PromoCode, error)
+	UpdatePromoCode(ID string, request dto.UpdatePromoCodeRequest) (*model.PromoCode, error)
+	// Apply checks that a code may be used by the registration and returns it with the discount
+	// it gives on the option. registrationID is the registration it is applied to, if stored already.
+	Apply(code string, registrationID, email string, option model.RegistrationOption) (*model.PromoCode, float64, int64, error)
+	// Redeem takes one use of the code applied to a stored registration.
+	Redeem(promoCodeID string, registration model.Registration) error
+}
+
+type promoCodeService struct {
+	promoCodeRepo repository.PromoCodeRepository
+	config        *config.Config
+}
+
+func (s promoCodeService) ListPromoCodes() ([]*model.PromoCode, error) {
+	return s.promoCodeRepo.List()
+}
+
+func (s promoCodeService) CreatePromoCode(request dto.CreatePromoCodeRequest) (*model.PromoCode, error) {
+	code := model.NormalizePromoCode(request.Code)
+	if code == "" {
+		return nil, errs.ErrInvalidArgument.Reform("code is required")
+	}
+	promoCode := model.PromoCode{Code: code}
+	if err := setPromoCode(&promoCode, request.PromoCodeRequest); err != nil {
+		return nil, err
+	}
+	return s.promoCodeRepo.Create(promoCode)
+}
+
+// UpdatePromoCode changes the terms of a code. Registrations already made keep their discount.
+func (s promoCodeService) UpdatePromoCode(ID string, request dto.UpdatePromoCodeRequest) (*model.PromoCode, error) {
+	promoCode, err := s.promoCodeRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if promoCode == nil {
+		return nil, errs.ErrNotFound.Reform("promo code not found")
+	}
+	if err := setPromoCode(promoCode, request.PromoCodeRequest); err != nil {
+		return nil, err
+	}
+	if err := s.promoCodeRepo.Update(*promoCode); err != nil {
+		return nil, err
+	}
+	return s.promoCodeRepo.GetByID(ID)
+}
+
+func setPromoCode(promoCode *model.PromoCode, request dto.PromoCodeRequest) error {
+	switch request.Type {
+	case model.DiscountTypePercentage:
+		if request.Percent <= 0 {
+			return errs.ErrInvalidArgument.Reform("percent must be greater than 0")
+		}
+	case model.DiscountTypeFixed:
+		if request.AmountUSD <= 0 && request.AmountVND <= 0 {
+			return errs.ErrInvalidArgument.Reform("amount_usd or amount_vnd must be greater than 0")
+		}
+		if !model.IsWholeUSD(request.AmountUSD) {
+			return errs.ErrInvalidArgument.Reform("amount_usd must be whole dollars")
+		}
+	}
+	if request.ValidFrom != nil && request.ValidUntil != nil && !request.ValidFrom.Before(*request.ValidUntil) {
+		return errs.ErrInvalidArgument.Reform("valid_from must be before valid_until")
+	}
+	promoCode.Description = request.Description
+	promoCode.Type = request.Type
+	promoCode.Percent = request.Percent
+	promoCode.AmountUSD = request.AmountUSD
+	promoCode.AmountVND = request.AmountVND
+	promoCode.MaxUses = request.MaxUses
+	promoCode.ValidFrom = utcTime(request.ValidFrom)
+	promoCode.ValidUntil = utcTime(request.ValidUntil)
+	promoCode.Categories = model.StringList(request.Categories)
+	promoCode.Email = strings.TrimSpace(request.Email)
+	promoCode.Active = request.Active
+	return nil
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func (s promoCodeService) Apply(code string, registrationID, email string, option model.RegistrationOption) (*model.PromoCode, float64, int64, error) {
+	promoCode, err := s.promoCodeRepo.GetByCode(code)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if promoCode == nil {
+		return nil, 0, 0, errs.ErrPromoCode.Reform("promo code %s does not exist", model.NormalizePromoCode(code))
+	}
+	if !promoCode.IsValidAt(time.Now().UTC()) {
+		return nil, 0, 0, errs.ErrPromoCode.Reform("promo code %s is not valid now", promoCode.Code)
+	}
+	if !promoCode.AcceptsCategory(option.Category) {
+		return nil, 0, 0, errs.ErrPromoCode.Reform("promo code %s is not valid for %s", promoCode.Code, option.Category)
+	}
+	if !promoCode.AcceptsEmail(email) {
+		return nil, 0, 0, errs.ErrPromoCode.Reform("promo code %s is not valid for this email", promoCode.Code)
+	}
+	if promoCode.MaxUses > 0 {
+		used, err := s.promoCodeRepo.CountUses(promoCode.Id, registrationID, s.pendingSince())
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if used >= promoCode.MaxUses {
+			return nil, 0, 0, errs.ErrPromoCode.Reform("promo code %s has been used up", promoCode.Code)
+		}
+	}
+	discountUSD, discountVND := promoCode.Discount(option.FeeUSD, option.FeeVND)
+	return promoCode, discountUSD, discountVND, nil
+}
+
+func (s promoCodeService) Redeem(promoCodeID string, registration model.Registration) error {
+	return s.promoCodeRepo.Redeem(promoCodeID, registration, s.pendingSince())
+}
+
+// pendingSince is when the oldest unpaid registration still holding a use was made:
+// one whose payment link has not expired yet.
+func (s promoCodeService) pendingSince() time.Time {
+	return time.Now().Add(-s.config.Payment.GetLinkTTL() - s.config.Payment.GetExpiryGrace())
+}
+
+var promoCodeServiceInstance PromoCodeService
+var promoCodeServiceOnce sync.Once
+
+func GetPromoCodeServiceInstance(promoCodeRepo repository.PromoCodeRepository, config *config.Config) PromoCodeService {
+	promoCodeServiceOnce.Do(func() {
+		promoCodeServiceInstance = NewPromoCodeService(promoCodeRepo, config)
+	})
+	return promoCodeServiceInstance
+}
+
+func NewPromoCodeService(promoCodeRepo repository.PromoCodeRepository, config *config.Config) PromoCodeService {
+	return &promoCodeService{
+		promoCodeRepo: promoCodeRepo,
+		config:        config,
+	}
+}
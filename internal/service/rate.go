@@ -1,70 +1,214 @@
 package service
 
 import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
 	"encoding/json"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 )
 
+const (
+	CurrencyUSD = "USD"
+	CurrencyVND = "VND"
+)
+
+// RateProvider returns how many VND one USD is worth.
+type RateProvider interface {
+	USDToVND() (float64, error)
+}
+
 type ExchangeResponse struct {
 	Rates  map[string]float64 `json:"rates"`
 	Result string             `json:"result"`
 }
 
-func setupRate() {
-	url := "https://open.er-api.com/v6/latest/USD"
+// httpRateProvider reads the rate from an open.er-api.com compatible endpoint.
+type httpRateProvider struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+func NewHTTPRateProvider(endpoint string, timeout time.Duration) RateProvider {
+	return &httpRateProvider{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
 
-	resp, err := http.Get(url)
+func (p *httpRateProvider) USDToVND() (float64, error) {
+	resp, err := p.httpClient.Get(p.endpoint)
 	if err != nil {
-		log.Println("err setupRate: ", err)
+		return 0, errs.ErrOtherService.Wrap(err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, errs.ErrOtherService.Reform("rate provider returned status %d", resp.StatusCode)
+	}
 
 	var data ExchangeResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		log.Println("err setupRate Decoder: ", err)
+		return 0, errs.ErrOtherService.Wrap(err)
 	}
-
 	if data.Result != "success" {
-		log.Println("err setupRate API error: ", data.Result)
+		return 0, errs.ErrOtherService.Reform("rate provider error: %s", data.Result)
+	}
+	vndRate, ok := data.Rates[CurrencyVND]
+	if !ok || vndRate <= 0 {
+		return 0, errs.ErrOtherService.Reform("VND rate not found")
 	}
+	return vndRate, nil
+}
 
-	vndRate, ok := data.Rates["VND"]
-	if !ok {
-		log.Println("VND rate not found")
+type fixedRateProvider struct {
+	rate float64
+}
+
+func NewFixedRateProvider(rate float64) RateProvider {
+	return &fixedRateProvider{rate: rate}
+}
+
+func (p *fixedRateProvider) USDToVND() (float64, error) {
+	if p.rate <= 0 {
+		return 0, errs.ErrInternal.Reform("fixed rate is not configured")
 	}
-	now := time.Now().UTC()
-	// Get midnight of the next day
-	midnight := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day()+1, // next day
-		0, 0, 0, 0,
-		now.Location(),
-	)
+	return p.rate, nil
+}
 
-	duration := midnight.Sub(now)
+// cachedRateProvider keeps the last rate for ttl. When the provider fails the last known
+// rate is used however old it is, and the fallback only when no rate was ever fetched.
+type cachedRateProvider struct {
+	provider RateProvider
+	fallback RateProvider
+	ttl      time.Duration
 
-	rate = rateUSDtoVND{
-		rate: vndRate,
-		at:   now,
-		ttl:  duration,
+	mu        sync.Mutex
+	rate      float64
+	fetchedAt time.Time
+}
+
+func NewCachedRateProvider(provider, fallback RateProvider, ttl time.Duration) RateProvider {
+	return &cachedRateProvider{
+		provider: provider,
+		fallback: fallback,
+		ttl:      ttl,
 	}
 }
 
-type rateUSDtoVND struct {
-	rate float64
-	at   time.Time
-	ttl  time.Duration
+func (p *cachedRateProvider) USDToVND() (float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rate > 0 && time.Since(p.fetchedAt) < p.ttl {
+		return p.rate, nil
+	}
+
+	rate, err := p.provider.USDToVND()
+	if err == nil {
+		p.rate = rate
+		p.fetchedAt = time.Now()
+		return rate, nil
+	}
+	if p.rate > 0 {
+		log.Printf("rate provider failed, using rate fetched at %s: %v", p.fetchedAt.UTC().Format(time.RFC3339), err)
+		return p.rate, nil
+	}
+	if p.fallback == nil {
+		return 0, err
+	}
+	log.Println("rate provider failed, using fallback rate: ", err)
+	return p.fallback.USDToVND()
 }
 
-var rate = rateUSDtoVND{
-	rate: 25995.239187,
-	at:   time.Now().Add(-time.Minute * 30),
-	ttl:  time.Minute * 30,
+// NewRateProvider builds the provider selected by the configuration.
+func NewRateProvider(cfg config.Rate) RateProvider {
+	fixed := NewFixedRateProvider(cfg.Fixed)
+	if cfg.Provider == "fixed" {
+		return fixed
+	}
+	return NewCachedRateProvider(NewHTTPRateProvider(cfg.Endpoint, cfg.GetTimeout()), fixed, cfg.GetTTL())
 }
 
-func CalculateVND(usd float64) float64 {
-	return usd * rate.rate
+// RateService is the RateProvider used for checkouts: a rate set by an admin wins
+// over the configured provider.
+type RateService interface {
+	RateProvider
+	GetExchangeRate() (*dto.ExchangeRateResponse, error)
+	SetOverride(rate float64, operator string) (*model.ExchangeRate, error)
+	ClearOverride() error
+}
+
+type rateService struct {
+	exchangeRateRepo repository.ExchangeRateRepository
+	provider         RateProvider
+}
+
+func (s rateService) USDToVND() (float64, error) {
+	rate, _, err := s.currentRate()
+	return rate, err
+}
+
+func (s rateService) currentRate() (float64, string, error) {
+	override, err := s.exchangeRateRepo.Get(CurrencyUSD, CurrencyVND)
+	if err != nil {
+		return 0, "", err
+	}
+	if override != nil {
+		return override.Rate, dto.ExchangeRateSourceOverride, nil
+	}
+	rate, err := s.provider.USDToVND()
+	if err != nil {
+		return 0, "", err
+	}
+	return rate, dto.ExchangeRateSourceProvider, nil
+}
+
+func (s rateService) GetExchangeRate() (*dto.ExchangeRateResponse, error) {
+	rate, source, err := s.currentRate()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ExchangeRateResponse{
+		Base:   CurrencyUSD,
+		Quote:  CurrencyVND,
+		Rate:   rate,
+		Source: source,
+	}, nil
+}
+
+func (s rateService) SetOverride(rate float64, operator string) (*model.ExchangeRate, error) {
+	if rate <= 0 {
+		return nil, errs.ErrBadRequest.Reform("invalid rate %v", rate)
+	}
+	return s.exchangeRateRepo.Save(model.ExchangeRate{
+		Base:      CurrencyUSD,
+		Quote:     CurrencyVND,
+		Rate:      rate,
+		UpdatedBy: operator,
+	})
+}
+
+func (s rateService) ClearOverride() error {
+	return s.exchangeRateRepo.Delete(CurrencyUSD, CurrencyVND)
+}
+
+var rateServiceInstance RateService
+var rateServiceOnce sync.Once
+
+func GetRateServiceInstance(exchangeRateRepo repository.ExchangeRateRepository, provider RateProvider) RateService {
+	rateServiceOnce.Do(func() {
+		rateServiceInstance = NewRateService(exchangeRateRepo, provider)
+	})
+	return rateServiceInstance
+}
+
+func NewRateService(exchangeRateRepo repository.ExchangeRateRepository, provider RateProvider) RateService {
+	return &rateService{
+		exchangeRateRepo: exchangeRateRepo,
+		provider:         provider,
+	}
 }
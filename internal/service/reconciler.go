@@ -0,0 +1,41 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"context"
+	"log"
+	"time"
+)
+
+// PaymentReconciler periodically sweeps payment attempts whose IPN never arrived.
+type PaymentReconciler struct {
+	registrationSvc RegistrationService
+	config          *config.Config
+}
+
+func NewPaymentReconciler(registrationSvc RegistrationService, config *config.Config) *PaymentReconciler {
+	return &PaymentReconciler{
+		registrationSvc: registrationSvc,
+		config:          config,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (p *PaymentReconciler) Run(ctx context.Context) {
+	if p.config.OnePay.QueryDREndpoint == "" && p.config.OnePayInternational.QueryDREndpoint == "" {
+		log.Println("Payment reconciler disabled: QueryDR endpoint not configured")
+		return
+	}
+	ticker := time.NewTicker(p.config.Reconcile.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.registrationSvc.ReconcilePendingPayments(); err != nil {
+				log.Printf("Reconcile pending payments failed: %s", err.Error())
+			}
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"ashno-onepay/internal/controller/dto"
 	errs "ashno-onepay/internal/errors"
 	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/onepay"
 	"ashno-onepay/internal/repository"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"net/url"
 	"strconv"
@@ -18,181 +20,430 @@ import (
 	"github.com/google/uuid"
 )
 
-const RateUSDVND = 26000
-
 type RegistrationService interface {
+	// Register returns the payment URL, empty when nothing is left to pay, and the registration ID.
 	Register(registration dto.RegistrationRequest, clientIP string) (string, string, error)
 	GetRegistration(ID string) (*model.Registration, error)
 	OnePayVerifySecureHash(u *url.URL) error
+	OnePayVerifyReturn(u *url.URL) (*dto.PaymentReturnResult, error)
 	GetRegistrationOption(filter model.RegistrationOptionFilter) (*model.RegistrationOption, error)
 	RegisterForAccompanyPersons(email string, accompanyPersons model.AccompanyPersonList, clientIP string) (string, error)
 	GetRegistrations(startTime, endTime time.Time) ([]*model.Registration, error)
+	ReconcilePendingPayments() error
+	GetPaymentTransactions(registrationID string) ([]*model.PaymentTransaction, error)
+	Refund(registrationID string, request dto.RefundRequest, operator string) (*model.Refund, error)
+	RenewPaymentURL(registrationID, clientIP string) (string, error)
+	RegisterGroup(request dto.GroupRegistrationRequest, clientIP string) (*dto.GroupRegistrationResponse, error)
+	GetGroup(ID string) (*model.RegistrationGroup, error)
+	RenewGroupPaymentURL(groupID, clientIP string) (string, error)
+	GetOfflinePayment(registrationID string) (*dto.OfflinePayment, error)
+	GetBankTransfer(registrationID string) (*dto.BankTransfer, error)
+	ConfirmOfflinePayment(registrationID string, request dto.ConfirmOfflinePaymentRequest, operator, clientIP string) (*model.Registration, error)
+	RequestCancellation(registrationID string, request dto.CancellationRequest) (*model.RegistrationChange, error)
+	RequestSubstitution(registrationID string, request dto.SubstitutionRequest) (*model.RegistrationChange, error)
+	ListRegistrationChanges(registrationID string) ([]*model.RegistrationChange, error)
+	ListChanges(status string) ([]*model.RegistrationChange, error)
+	ApproveChange(ID string, request dto.DecideChangeRequest, operator string) (*model.RegistrationChange, error)
+	RejectChange(ID string, request dto.DecideChangeRequest, operator string) (*model.RegistrationChange, error)
+	GetAuditLog(registrationID string) ([]*model.AuditLog, error)
+	ImportRegistrations(records [][]string, request dto.ImportRegistrationsRequest, operator, clientIP string) (*dto.ImportRegistrationsResponse, error)
+	JoinWaitlist(request dto.RegistrationRequest) (*model.WaitlistEntry, error)
+	GetWaitlistEntry(ID string) (*model.WaitlistEntry, error)
+	ListWaitlist(category, status string) ([]*model.WaitlistEntry, error)
+	InviteFromWaitlist() error
+	GetManageableRegistration(email string) (*model.Registration, error)
+	SendManageLink(reg *model.Registration, link string)
+	GetManagedRegistration(registrationID, email string) (*model.Registration, error)
+	UpdateDetails(registrationID, email string, request dto.UpdateDetailsRequest) (*model.Registration, error)
+	CheckIn(request dto.CheckInRequest, operatorID string) (*dto.CheckInResponse, error)
 }
 
 type registrationService struct {
 	registrationRepo        repository.RegistrationRepository
 	registrationOptionsRepo repository.RegistrationOptionRepository
+	paymentTransactionRepo  repository.PaymentTransactionRepository
+	refundRepo              repository.RefundRepository
+	registrationGroupRepo   repository.RegistrationGroupRepository
+	registrationChangeRepo  repository.RegistrationChangeRepository
+	auditLogRepo            repository.AuditLogRepository
+	categoryCapacityRepo    repository.CategoryCapacityRepository
+	waitlistRepo            repository.WaitlistRepository
+	onePay                  map[model.PaymentMethod]*onepay.Client
+	rateProvider            RateProvider
+	periodSvc               PeriodService
+	addOnSvc                AddOnService
+	promoCodeSvc            PromoCodeService
 	config                  *config.Config
 }
 
+// GetRegistrationOption prices a registration before it is made: the option of the category
+// for the current period plus the gala dinner for the registrant and the accompanying persons.
+// A promo code is taken off the option fee. Once the email has a paid registration only the
+// accompanying persons' dinners are priced.
 func (r registrationService) GetRegistrationOption(filter model.RegistrationOptionFilter) (*model.RegistrationOption, error) {
-	var registrationOption *model.RegistrationOption
 	reg, err := r.registrationRepo.GetByEmail(filter.Email)
 	if err != nil {
 		return nil, err
 	}
+	subtype := ""
+	period, err := r.periodSvc.CurrentPeriod()
+	if err == nil {
+		subtype = period.Name
+	}
 	if (reg == nil || reg.PaymentStatus != string(model.PaymentStatusDone)) && filter.Category != "" {
+		if err != nil {
+			return nil, err
+		}
 		switch filter.Category {
 		case string(model.DoctorCategory):
-			filter.Category = string(model.DoctorCategory)
-			if filter.AttendGalaDinner {
-				filter.Category = string(model.DoctorAndDinnerCategory)
-			}
-			filter.Subtype = DetermineRegistrationPeriod(time.Now())
+			filter.Subtype = period.Name
 		case string(model.StudentCategory):
-			filter.Category = string(model.StudentCategory)
-			if filter.AttendGalaDinner {
-				filter.Category = string(model.StudentAndDinnerCategory)
-			}
 		default:
 			return nil, errs.ErrNotFound.Reform("option not found")
 		}
-		registrationOption, err = r.registrationOptionsRepo.Find(filter)
+		registrationOption, err := r.registrationOptionsRepo.Find(filter)
 		if err != nil {
 			return nil, err
 		}
-		if filter.NumberAccompanyPersons > 0 {
-			registrationOption.FeeUSD = registrationOption.FeeUSD + float64(filter.NumberAccompanyPersons)*model.GalaDinnerOnlyOption.FeeUSD
-			registrationOption.FeeVND = registrationOption.FeeVND + int64(filter.NumberAccompanyPersons)*model.GalaDinnerOnlyOption.FeeVND
+		if filter.PromoCode != "" {
+			regID := ""
+			if reg != nil {
+				regID = reg.Id
+			}
+			_, discountUSD, discountVND, err := r.promoCodeSvc.Apply(filter.PromoCode, regID, filter.Email, *registrationOption)
+			if err != nil {
+				return nil, err
+			}
+			registrationOption.FeeUSD -= discountUSD
+			registrationOption.FeeVND -= discountVND
+		}
+		dinners := filter.NumberAccompanyPersons
+		if filter.AttendGalaDinner {
+			dinners++
+		}
+		if dinners > 0 {
+			price, err := r.addOnSvc.GalaDinnerPrice(subtype)
+			if err != nil {
+				return nil, err
+			}
+			registrationOption.FeeUSD = registrationOption.FeeUSD + float64(dinners)*price.FeeUSD
+			registrationOption.FeeVND = registrationOption.FeeVND + int64(dinners)*price.FeeVND
 		}
 		return registrationOption, nil
 	}
+	price, err := r.addOnSvc.GalaDinnerPrice(subtype)
+	if err != nil {
+		return nil, err
+	}
 	return &model.RegistrationOption{
-		FeeUSD:   float64(filter.NumberAccompanyPersons) * model.GalaDinnerOnlyOption.FeeUSD,
-		FeeVND:   int64(filter.NumberAccompanyPersons) * model.GalaDinnerOnlyOption.FeeVND,
-		Category: string(model.GalaDinnerOnlyOption.Category),
+		FeeUSD:   float64(filter.NumberAccompanyPersons) * price.FeeUSD,
+		FeeVND:   int64(filter.NumberAccompanyPersons) * price.FeeVND,
+		Category: model.AddOnCodeGalaDinner,
 	}, nil
 }
 
 func (r registrationService) OnePayVerifySecureHash(u *url.URL) error {
-	queryParams := u.Query()
-	queryParamsMap := make(map[string]string)
-	for k, v := range queryParams {
-		queryParamsMap[k] = strings.Join(v, "")
+	result, err := r.verifyCallback(u.Query())
+	if err != nil {
+		return err
 	}
+	return r.applyPaymentResult(*result)
+}
 
-	// Extract required fields
-	txnRef := queryParamsMap["vpc_MerchTxnRef"]
-	orderInfo := queryParamsMap["vpc_OrderInfo"]
-	txnCode := queryParamsMap["vpc_TxnResponseCode"]
-	merchantSecureHash := queryParamsMap["vpc_SecureHash"]
-	message := queryParamsMap["vpc_Message"]
+// OnePayVerifyReturn checks the query OnePay redirects the payer back with. It does not change
+// any state: the result is applied by the IPN or the reconciler, the payer is only told about it.
+func (r registrationService) OnePayVerifyReturn(u *url.URL) (*dto.PaymentReturnResult, error) {
+	result, err := r.verifyCallback(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	registrationID, groupID := result.MerchTxnRef, ""
+	transaction, err := r.paymentTransactionRepo.GetByMerchTxnRef(result.MerchTxnRef, result.OrderInfo)
+	if err != nil {
+		return nil, err
+	}
+	if transaction != nil {
+		registrationID, groupID = transaction.RegistrationID, transaction.GroupID
+	}
+	outcome := onepay.OutcomeOf(result.ResponseCode)
+	if transaction != nil && outcome == onepay.OutcomeSuccess && transaction.IsExpired(time.Now().Add(-r.config.Payment.GetExpiryGrace())) {
+		outcome = onepay.OutcomeExpired
+	}
+	return &dto.PaymentReturnResult{
+		RegistrationID: registrationID,
+		GroupID:        groupID,
+		Result:         string(outcome),
+		ResponseCode:   result.ResponseCode,
+		Message:        onepay.Describe(result.ResponseCode),
+		MerchTxnRef:    result.MerchTxnRef,
+		OrderInfo:      result.OrderInfo,
+	}, nil
+}
 
-	if txnRef == "" {
-		return errs.ErrBadRequest
+// verifyCallback checks a OnePay callback with the merchant profile its attempt was made with.
+// Attempts recorded before profiles existed are tried against the configured profiles whose
+// merchant is the callback's vpc_Merchant.
+func (r registrationService) verifyCallback(query url.Values) (*onepay.PaymentResult, error) {
+	transaction, err := r.paymentTransactionRepo.GetByMerchTxnRef(query.Get("vpc_MerchTxnRef"), query.Get("vpc_OrderInfo"))
+	if err != nil {
+		return nil, err
+	}
+	if transaction != nil && transaction.PaymentMethod != "" {
+		return r.onePayClient(transaction.PaymentMethod).VerifyCallback(query)
+	}
+	var lastErr error = onepay.ErrInvalidSignature
+	for _, method := range []model.PaymentMethod{model.PaymentMethodOnePayDomestic, model.PaymentMethodOnePayInternational} {
+		client := r.onePay[method]
+		if client == nil || client.MerchantID() != query.Get("vpc_Merchant") {
+			continue
+		}
+		result, err := client.VerifyCallback(query)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
 	}
+	return nil, lastErr
+}
 
-	// Verify secure hash
-	op := r.config.OnePay
-	queryMapSorted := sortParams(queryParamsMap)
-	stringToHash := generateStringToHash(queryMapSorted)
-	onePaySecureHash := generateSecureHash(stringToHash, op.HashCode)
-	log.Println("OnePay's Hash: ", onePaySecureHash)
-	log.Println("Merchant's Hash: ", merchantSecureHash)
-	if onePaySecureHash != merchantSecureHash {
-		return errs.ErrForbidden.Reform("Invalid signature")
+// onePayClient returns the merchant a payment method is paid through, the domestic one when the
+// method has no merchant configured.
+func (r registrationService) onePayClient(method string) *onepay.Client {
+	if client, ok := r.onePay[model.PaymentMethod(method)]; ok {
+		return client
 	}
+	return r.onePay[model.PaymentMethodOnePayDomestic]
+}
+
+// applyPaymentResult applies a verified OnePay result to its transaction. It is shared by
+// the IPN handler and the reconciler so a result is handled the same way whatever its source.
+func (r registrationService) applyPaymentResult(result onepay.PaymentResult) error {
+	txnRef := result.MerchTxnRef
+	orderInfo := result.OrderInfo
+	txnCode := result.ResponseCode
+	message := result.Message
 
-	// Handle payment result by order type
+	var orderType model.OrderType
 	switch {
-	case strings.HasPrefix(orderInfo, "ORDER"):
-		// Main registration payment
-		regID := txnRef
-		reg, err := r.registrationRepo.GetRegistration(regID)
-		if err != nil {
-			return err
+	case strings.HasPrefix(orderInfo, string(model.OrderTypeRegistration)):
+		orderType = model.OrderTypeRegistration
+	case strings.HasPrefix(orderInfo, string(model.OrderTypeAccompanyPerson)):
+		orderType = model.OrderTypeAccompanyPerson
+	case strings.HasPrefix(orderInfo, string(model.OrderTypeGroup)):
+		orderType = model.OrderTypeGroup
+	default:
+		return nil
+	}
+
+	// OnePay retries the IPN until it gets a confirmation, so only the first
+	// callback for a transaction is allowed to change state or send emails.
+	transaction, err := r.findOrCreatePaymentTransaction(orderType, result)
+	if err != nil {
+		return err
+	}
+	if transaction.Status == string(model.PaymentTransactionStatusExpired) {
+		if result.Succeeded() {
+			log.Printf("Payment %s/%s succeeded after it was closed and must be refunded", txnRef, orderInfo)
 		}
-		if reg == nil {
-			return errs.ErrNotFound.Reform("registration not found")
-		}
-		var status string
-		if txnCode == "0" {
-			log.Println("Payment Success for ", regID)
-			status = string(model.PaymentStatusDone)
-			// Mark all accompany persons as paid if they were pending
-			for i := range reg.AccompanyPersons {
-				if reg.AccompanyPersons[i].PaymentStatus == model.AccompanyPersonsPaymentStatusPending {
-					reg.AccompanyPersons[i].PaymentStatus = model.AccompanyPersonsPaymentStatusDone
-				}
-			}
-			err = r.registrationRepo.UpdateAccompanyPersonsByID(reg.Id, reg.AccompanyPersons)
-			if err != nil {
-				return err
-			}
-			// Send registration email in background
-			go func() {
-				var registrationFee, locale string
-				if reg.Nationality == model.NationalityVietNam {
-					registrationFee = strconv.FormatInt(reg.RegistrationOption.FeeVND, 10) + " VND"
-					locale = "vi"
-				} else {
-					registrationFee = strconv.FormatFloat(float64(reg.RegistrationOption.FeeUSD), 'f', -1, 64) + " USD"
-					locale = "en"
-				}
-				fullName := fmt.Sprintf("%s %s %s", reg.FirstName, reg.MiddleName, reg.LastName)
-				err := SendRegistrationEmailWithTemplate(
-					reg.Email, reg.FirstName, reg.Id, locale, fullName, reg.PhoneNumber, registrationFee, r.config,
-				)
-				if err != nil {
-					log.Printf("Send QR Failed for %s", err.Error())
-					log.Printf("Send QR Failed for %s", reg.Id)
-				}
-			}()
-		} else {
-			log.Printf("Payment Failed for %s: %s", regID, message)
-			status = string(model.PaymentStatusFail)
+		return errs.ErrPaymentExpired
+	}
+	if transaction.Status != string(model.PaymentTransactionStatusPending) {
+		log.Printf("Payment %s/%s already processed", txnRef, orderInfo)
+		return nil
+	}
+	status := model.PaymentTransactionStatusFail
+	if result.Succeeded() {
+		status = model.PaymentTransactionStatusSuccess
+	}
+	expired := transaction.IsExpired(time.Now().Add(-r.config.Payment.GetExpiryGrace()))
+	if expired {
+		status = model.PaymentTransactionStatusExpired
+	}
+	claimed, err := r.paymentTransactionRepo.CompletePending(transaction.Id, model.PaymentTransactionResult{
+		Status:       string(status),
+		ResponseCode: txnCode,
+		Message:      message,
+		RawQuery:     result.RawQuery,
+	})
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Printf("Payment %s/%s already processed", txnRef, orderInfo)
+		return nil
+	}
+	if expired {
+		if result.Succeeded() {
+			log.Printf("Payment %s/%s succeeded after its link expired and must be refunded", txnRef, orderInfo)
 		}
-		return r.registrationRepo.UpdatePaymentStatus(regID, status)
+		return errs.ErrPaymentExpired
+	}
 
-	case strings.HasPrefix(orderInfo, "ACCOM"):
-		// Accompany person payment
-		transactionID := strings.TrimPrefix(orderInfo, "ACCOM")
+	var sendEmail func()
+	switch orderType {
+	case model.OrderTypeRegistration:
+		sendEmail, err = r.applyRegistrationPayment(transaction.RegistrationID, txnCode, message)
+	case model.OrderTypeAccompanyPerson:
+		err = r.applyAccompanyPersonPayment(transaction.RegistrationID, orderInfo, txnCode, message)
+	case model.OrderTypeGroup:
+		sendEmail, err = r.applyGroupPayment(transaction.GroupID, txnCode, message)
+	}
+	if err != nil {
+		// Hand the transaction back so the next IPN retry can apply it again.
+		if resetErr := r.paymentTransactionRepo.ResetPending(transaction.Id); resetErr != nil {
+			log.Printf("Reset payment transaction %s failed: %s", transaction.Id, resetErr.Error())
+		}
+		return err
+	}
+	if sendEmail != nil {
+		go sendEmail()
+	}
+	return nil
+}
+
+// findOrCreatePaymentTransaction returns the ledger entry for a OnePay callback.
+// Attempts generated before the ledger existed have no row yet, so one is created from the callback.
+func (r registrationService) findOrCreatePaymentTransaction(orderType model.OrderType, result onepay.PaymentResult) (*model.PaymentTransaction, error) {
+	txnRef := result.MerchTxnRef
+	orderInfo := result.OrderInfo
+	transaction, err := r.paymentTransactionRepo.GetByMerchTxnRef(txnRef, orderInfo)
+	if err != nil {
+		return nil, err
+	}
+	if transaction != nil {
+		return transaction, nil
+	}
+
+	var regID string
+	switch orderType {
+	case model.OrderTypeRegistration:
+		regID = txnRef
+	case model.OrderTypeAccompanyPerson:
+		transactionID := strings.TrimPrefix(orderInfo, string(model.OrderTypeAccompanyPerson))
 		accompanyPersons, err := r.registrationRepo.GetAccompanyPersonsByTransactionAndRegistration(transactionID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if len(accompanyPersons) == 0 {
-			return err
+			return nil, errs.ErrNotFound.Reform("accompany persons not found")
 		}
-		regID := accompanyPersons[0].RegistrationID
-		reg, err := r.registrationRepo.GetRegistration(regID)
-		if err != nil {
-			return err
+		regID = accompanyPersons[0].RegistrationID
+	case model.OrderTypeGroup:
+		// group payments were recorded in the ledger from the start
+		return nil, errs.ErrNotFound.Reform("payment transaction not found")
+	}
+	return r.paymentTransactionRepo.Create(model.PaymentTransaction{
+		RegistrationID: regID,
+		MerchTxnRef:    txnRef,
+		OrderInfo:      orderInfo,
+		OrderType:      string(orderType),
+		Amount:         result.Amount,
+		Currency:       result.Params["vpc_Currency"],
+		Status:         string(model.PaymentTransactionStatusPending),
+	})
+}
+
+// applyRegistrationPayment moves the registration to its final payment status and
+// returns the confirmation email to send once the change is stored.
+func (r registrationService) applyRegistrationPayment(regID, txnCode, message string) (func(), error) {
+	reg, err := r.registrationRepo.GetRegistration(regID)
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		return nil, errs.ErrNotFound.Reform("registration not found")
+	}
+	if txnCode != onepay.ResponseCodeSuccess {
+		log.Printf("Payment Failed for %s: %s", regID, message)
+		if reg.PaymentStatus == string(model.PaymentStatusDone) {
+			return nil, nil
 		}
-		if reg == nil {
-			return errs.ErrNotFound.Reform("registration not found")
-		}
-		for _, person := range accompanyPersons {
-			reg.AccompanyPersons = append(reg.AccompanyPersons, model.AccompanyPerson{
-				FirstName:     person.FirstName,
-				MiddleName:    person.MiddleName,
-				LastName:      person.LastName,
-				DateOfBirth:   person.DateOfBirth,
-				PaymentStatus: model.AccompanyPersonsPaymentStatusDone,
-			})
-		}
-		if txnCode == "0" {
-			log.Println("Payment Success for accompany person ", regID)
-			err = r.registrationRepo.UpdateAccompanyPersonsByID(reg.Id, reg.AccompanyPersons)
-			if err != nil {
-				return err
-			}
-		} else {
-			log.Printf("Accompany person payment failed for %s: %s", regID, message)
+		// the add-ons are given back with the seat, a renewed payment reserves them again
+		if err := r.addOnSvc.MarkLineItems(reg.Id, "", model.LineItemStatusFail); err != nil {
+			return nil, err
 		}
+		return nil, r.registrationRepo.UpdatePaymentStatus(regID, string(model.PaymentStatusFail))
 	}
 
-	return nil
+	log.Println("Payment Success for ", regID)
+	// Mark all accompany persons as paid if they were pending
+	for i := range reg.AccompanyPersons {
+		if reg.AccompanyPersons[i].PaymentStatus == model.AccompanyPersonsPaymentStatusPending {
+			reg.AccompanyPersons[i].PaymentStatus = model.AccompanyPersonsPaymentStatusDone
+		}
+	}
+	err = r.registrationRepo.UpdateAccompanyPersonsByID(reg.Id, reg.AccompanyPersons)
+	if err != nil {
+		return nil, err
+	}
+	err = r.addOnSvc.MarkLineItems(reg.Id, "", model.LineItemStatusDone)
+	if err != nil {
+		return nil, err
+	}
+	err = r.registrationRepo.UpdatePaymentStatus(regID, string(model.PaymentStatusDone))
+	if err != nil {
+		return nil, err
+	}
+	// Send registration email in background
+	return func() {
+		r.sendConfirmationEmail(reg)
+	}, nil
+}
+
+// sendConfirmationEmail sends the attendee of a paid registration their ticket QR code.
+func (r registrationService) sendConfirmationEmail(reg *model.Registration) {
+	var registrationFee, locale string
+	feeUSD, feeVND := reg.Fee()
+	if reg.Nationality == model.NationalityVietNam {
+		registrationFee = strconv.FormatInt(feeVND, 10) + " VND"
+		locale = "vi"
+	} else {
+		registrationFee = strconv.FormatFloat(feeUSD, 'f', -1, 64) + " USD"
+		locale = "en"
+	}
+	fullName := fmt.Sprintf("%s %s %s", reg.FirstName, reg.MiddleName, reg.LastName)
+	err := SendRegistrationEmailWithTemplate(
+		reg.Email, reg.FirstName, reg.TicketPath(), locale, fullName, reg.PhoneNumber, registrationFee, r.config,
+	)
+	if err != nil {
+		log.Printf("Send QR Failed for %s", err.Error())
+		log.Printf("Send QR Failed for %s", reg.Id)
+	}
+}
+
+// applyAccompanyPersonPayment adds the accompany persons bought in a separate
+// transaction to the registration once that transaction succeeds.
+func (r registrationService) applyAccompanyPersonPayment(regID, orderInfo, txnCode, message string) error {
+	transactionID := strings.TrimPrefix(orderInfo, string(model.OrderTypeAccompanyPerson))
+	if txnCode != onepay.ResponseCodeSuccess {
+		log.Printf("Accompany person payment failed for %s: %s", regID, message)
+		return r.addOnSvc.MarkLineItems(regID, transactionID, model.LineItemStatusFail)
+	}
+	if err := r.addOnSvc.MarkLineItems(regID, transactionID, model.LineItemStatusDone); err != nil {
+		return err
+	}
+	accompanyPersons, err := r.registrationRepo.GetAccompanyPersonsByTransactionAndRegistration(transactionID)
+	if err != nil {
+		return err
+	}
+	reg, err := r.registrationRepo.GetRegistration(regID)
+	if err != nil {
+		return err
+	}
+	if reg == nil {
+		return errs.ErrNotFound.Reform("registration not found")
+	}
+	for _, person := range accompanyPersons {
+		reg.AccompanyPersons = append(reg.AccompanyPersons, model.AccompanyPerson{
+			FirstName:     person.FirstName,
+			MiddleName:    person.MiddleName,
+			LastName:      person.LastName,
+			DateOfBirth:   person.DateOfBirth,
+			PaymentStatus: model.AccompanyPersonsPaymentStatusDone,
+		})
+	}
+	log.Println("Payment Success for accompany person ", regID)
+	return r.registrationRepo.UpdateAccompanyPersonsByID(reg.Id, reg.AccompanyPersons)
 }
 
 func (r registrationService) GetRegistration(ID string) (*model.Registration, error) {
@@ -203,47 +454,146 @@ func (r registrationService) GetRegistration(ID string) (*model.Registration, er
 	if reg == nil {
 		return nil, errs.ErrNotFound.Reform("registration not found")
 	}
-	if len(reg.AccompanyPersons) > 0 {
-		reg.RegistrationOption.FeeUSD = reg.RegistrationOption.FeeUSD + float64(len(reg.AccompanyPersons))*model.GalaDinnerOnlyOption.FeeUSD
-		reg.RegistrationOption.FeeVND = reg.RegistrationOption.FeeVND + int64(len(reg.AccompanyPersons))*model.GalaDinnerOnlyOption.FeeVND
-	}
+	// the option fee is shown with the add-ons included
+	reg.RegistrationOption.FeeUSD, reg.RegistrationOption.FeeVND = reg.Fee()
 	return reg, nil
 }
 
 func (r registrationService) Register(request dto.RegistrationRequest, clientIP string) (string, string, error) {
 	// check email registered
-	oldReg, err := r.registrationRepo.GetByEmail(request.Email)
+	oldReg, err := r.replaceableRegistration(request.Email)
 	if err != nil {
 		return "", "", err
 	}
+	// the old request holds the email, so it is removed first and put back if the new one
+	// cannot be made
+	var replaced []model.Registration
 	if oldReg != nil {
-		if oldReg.PaymentStatus == string(model.PaymentStatusDone) {
-			return "", "", errs.ErrInternal.Reform("email registered")
-		}
 		err = r.registrationRepo.Remove(oldReg.Id)
 		if err != nil {
 			return "", "", err
 		}
+		replaced = append(replaced, *oldReg)
 	}
 
 	// setup registration
 	reg, err := r.setupRegistration(request)
 	if err != nil {
+		r.restoreRegistrations(replaced)
 		return "", "", err
 	}
 	// generate paymentURL
-	paymentURL, err := r.generatePaymentURL(&reg, clientIP)
-	if err != nil {
+	var paymentURL string
+	var transaction model.PaymentTransaction
+	paysOnePay := model.PaymentMethod(reg.PaymentMethod).IsOnePay()
+	if !nothingToPay(reg) && paysOnePay {
+		paymentURL, transaction, err = r.generatePaymentURL(&reg, reg.Id, clientIP)
+		if err != nil {
+			r.restoreRegistrations(replaced)
+			return "", "", err
+		}
+	}
+	// insert registration
+	if err = r.createRegistration(reg); err != nil {
+		r.restoreRegistrations(replaced)
 		return "", "", err
 	}
-	// remove old request + insert registration
-	_, err = r.registrationRepo.Create(reg)
+	if nothingToPay(reg) {
+		return "", reg.Id, r.completeWithoutPayment(&reg, clientIP)
+	}
+	// bank transfers and cash stay pending until an admin confirms receipt
+	if !paysOnePay {
+		return "", reg.Id, nil
+	}
+	_, err = r.paymentTransactionRepo.Create(transaction)
 	if err != nil {
+		// nobody can pay it, so its seat, add-ons and promo code are given back
+		r.discardRegistration(reg.Id)
+		r.restoreRegistrations(replaced)
 		return "", "", err
 	}
 	return paymentURL, reg.Id, nil
 }
 
+// replaceableRegistration returns the unpaid registration a new registration with email replaces,
+// failing when the email has a paid, refunded or cancelled registration or one made by a sponsor group.
+func (r registrationService) replaceableRegistration(email string) (*model.Registration, error) {
+	oldReg, err := r.registrationRepo.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if oldReg == nil {
+		return nil, nil
+	}
+	if oldReg.PaymentStatus != string(model.PaymentStatusPending) && oldReg.PaymentStatus != string(model.PaymentStatusFail) {
+		return nil, errs.ErrInternal.Reform("email registered")
+	}
+	if oldReg.GroupID != "" {
+		return nil, errs.ErrBadRequest.Reform("email registered by a sponsor group")
+	}
+	return oldReg, nil
+}
+
+// discardRegistration removes a registration whose add-ons or promo code could not be reserved.
+func (r registrationService) discardRegistration(ID string) {
+	if err := r.registrationRepo.Remove(ID); err != nil {
+		log.Printf("Remove registration %s failed: %s", ID, err.Error())
+	}
+}
+
+// nothingToPay reports whether the registration fee, in the currency the registrant is charged in,
+// is fully covered, e.g. waived by a promo code.
+func nothingToPay(reg model.Registration) bool {
+	feeUSD, feeVND := reg.Fee()
+	return chargesNothing(reg.Nationality, feeUSD, feeVND)
+}
+
+func chargesNothing(nationality string, feeUSD float64, feeVND int64) bool {
+	if nationality == model.NationalityVietNam {
+		return feeVND <= 0
+	}
+	return feeUSD <= 0
+}
+
+// completeWithoutPayment marks a registration with nothing to pay as paid without a OnePay
+// round-trip. The ledger records it as a successful attempt of 0.
+func (r registrationService) completeWithoutPayment(reg *model.Registration, clientIP string) error {
+	_, currency := amountDue(reg)
+	return r.settleOutsideOnePay(reg, 0, currency, "nothing to pay", clientIP)
+}
+
+// settleOutsideOnePay marks a registration paid without a OnePay round-trip and records amount
+// in currency as a successful attempt on the ledger.
+func (r registrationService) settleOutsideOnePay(reg *model.Registration, amount int64, currency, message, clientIP string) error {
+	now := time.Now().UTC()
+	orderRef := RandomString(16)
+	_, err := r.paymentTransactionRepo.Create(model.PaymentTransaction{
+		RegistrationID: reg.Id,
+		MerchTxnRef:    orderRef,
+		OrderInfo:      fmt.Sprintf("%s%s", model.OrderTypeRegistration, orderRef),
+		OrderType:      string(model.OrderTypeRegistration),
+		PaymentMethod:  reg.PaymentMethod,
+		Amount:         amount,
+		Currency:       currency,
+		ClientIP:       clientIP,
+		Status:         string(model.PaymentTransactionStatusSuccess),
+		ResponseCode:   onepay.ResponseCodeSuccess,
+		Message:        message,
+		CompletedAt:    &now,
+	})
+	if err != nil {
+		return err
+	}
+	sendEmail, err := r.applyRegistrationPayment(reg.Id, onepay.ResponseCodeSuccess, message)
+	if err != nil {
+		return err
+	}
+	if sendEmail != nil {
+		go sendEmail()
+	}
+	return nil
+}
+
 func (r registrationService) setupRegistration(request dto.RegistrationRequest) (model.Registration, error) {
 	reg := model.Registration{
 		RegistrationCategory: request.RegistrationCategory,
@@ -257,26 +607,40 @@ func (r registrationService) setupRegistration(request dto.RegistrationRequest)
 		Email:                request.Email,
 		PhoneNumber:          request.PhoneNumber,
 		Sponsor:              request.Sponsor,
+		PaymentMethod:        request.PaymentMethod,
 		AccompanyPersons:     []model.AccompanyPerson{},
 	}
+	if reg.PaymentMethod == "" {
+		reg.PaymentMethod = string(model.DefaultPaymentMethod(reg.Nationality))
+	}
+	if reg.PaymentMethod == string(model.PaymentMethodBankTransfer) {
+		if !r.config.BankTransfer.Enabled() {
+			return model.Registration{}, errs.ErrBadRequest.Reform("bank transfer is not available")
+		}
+		if reg.Nationality != model.NationalityVietNam {
+			return model.Registration{}, errs.ErrInvalidArgument.Reform("bank transfer is only available for payments in VND")
+		}
+	}
 	for _, p := range request.AccompanyPersons {
 		p.PaymentStatus = model.AccompanyPersonsPaymentStatusPending
 		reg.AccompanyPersons = append(reg.AccompanyPersons, p)
 	}
 	reg.Id = uuid.New().String()
+	// attendees paying in VND can pay by bank transfer, whatever their payment method
+	if reg.Nationality == model.NationalityVietNam && r.config.BankTransfer.Enabled() {
+		reg.TransferReference = r.transferReference(reg.Id)
+	}
+	period, err := r.periodSvc.CurrentPeriod()
+	if err != nil {
+		return model.Registration{}, err
+	}
 	OptionFilter := model.RegistrationOptionFilter{}
 	switch request.RegistrationOption {
 	case string(model.DoctorCategory):
 		OptionFilter.Category = string(model.DoctorCategory)
-		if request.AttendGalaDinner {
-			OptionFilter.Category = string(model.DoctorAndDinnerCategory)
-		}
-		OptionFilter.Subtype = DetermineRegistrationPeriod(time.Now())
+		OptionFilter.Subtype = period.Name
 	case string(model.StudentCategory):
 		OptionFilter.Category = string(model.StudentCategory)
-		if request.AttendGalaDinner {
-			OptionFilter.Category = string(model.StudentAndDinnerCategory)
-		}
 	default:
 		return model.Registration{}, errs.ErrNotFound.Reform("option not found")
 	}
@@ -287,10 +651,80 @@ func (r registrationService) setupRegistration(request dto.RegistrationRequest)
 	}
 	reg.RegistrationOptionID = option.Id
 	reg.RegistrationOption = *option
+	if request.PromoCode != "" {
+		if err := r.applyPromoCode(&reg, request.PromoCode); err != nil {
+			return model.Registration{}, err
+		}
+	}
 
+	reg.LineItems, err = r.addOnSvc.PriceLineItems(reg.Id, "", period.Name, registrationAddOns(request))
+	if err != nil {
+		return model.Registration{}, err
+	}
+	reservedUntil := r.seatHold(reg.PaymentMethod)
+	reg.ReservedUntil = &reservedUntil
 	return reg, nil
 }
 
+// seatHold is until when an unpaid registration holds its seat: the life of its payment link, or
+// longer for a bank transfer or cash payment an admin has to confirm.
+func (r registrationService) seatHold(paymentMethod string) time.Time {
+	if model.PaymentMethod(paymentMethod).IsOnePay() {
+		return time.Now().UTC().Add(r.config.Payment.GetLinkTTL() + r.config.Payment.GetExpiryGrace())
+	}
+	return time.Now().UTC().Add(r.config.Capacity.GetOfflineHold())
+}
+
+// renewSeat holds the seat of an unpaid registration for a new payment attempt. A registration
+// whose seat was freed only gets one back if no one on the waitlist is owed it.
+func (r registrationService) renewSeat(reg *model.Registration) error {
+	category := reg.RegistrationOption.Category
+	heldSeats, err := r.waitlistRepo.CountWaiting(category)
+	if err != nil {
+		return err
+	}
+	return r.registrationRepo.RenewSeat(reg.Id, category, r.seatHold(reg.PaymentMethod), heldSeats)
+}
+
+// registrationAddOns lists the add-ons ordered with a registration: the requested ones, the gala
+// dinner when attend_gala_dinner is set and a gala dinner for each accompanying person.
+func registrationAddOns(request dto.RegistrationRequest) []dto.AddOnRequest {
+	addOns := append([]dto.AddOnRequest{}, request.AddOns...)
+	if request.AttendGalaDinner && !hasAddOn(addOns, model.AddOnCodeGalaDinner) {
+		addOns = append(addOns, dto.AddOnRequest{Code: model.AddOnCodeGalaDinner, Quantity: 1})
+	}
+	if len(request.AccompanyPersons) > 0 {
+		addOns = append(addOns, dto.AddOnRequest{
+			Code:             model.AddOnCodeGalaDinner,
+			Quantity:         len(request.AccompanyPersons),
+			AccompanyPersons: true,
+		})
+	}
+	return addOns
+}
+
+func hasAddOn(addOns []dto.AddOnRequest, code string) bool {
+	for _, addOn := range addOns {
+		if addOn.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPromoCode takes the discount of a promo code off the registration option fee.
+func (r registrationService) applyPromoCode(reg *model.Registration, code string) error {
+	promoCode, discountUSD, discountVND, err := r.promoCodeSvc.Apply(code, reg.Id, reg.Email, reg.RegistrationOption)
+	if err != nil {
+		return err
+	}
+	reg.PromoCodeID = promoCode.Id
+	reg.PromoCode = promoCode.Code
+	reg.DiscountUSD = discountUSD
+	reg.DiscountVND = discountVND
+	return nil
+}
+
 func (r registrationService) RegisterForAccompanyPersons(email string, accompanyPersons model.AccompanyPersonList, clientIP string) (string, error) {
 	reg, err := r.registrationRepo.GetByEmail(email)
 	if err != nil {
@@ -300,6 +734,37 @@ func (r registrationService) RegisterForAccompanyPersons(email string, accompany
 		return "", errs.ErrNotFound.Reform("registration with email %s not found", email)
 	}
 	transactionID := RandomString(16)
+	subtype := ""
+	if period, err := r.periodSvc.CurrentPeriod(); err == nil {
+		subtype = period.Name
+	}
+	items, err := r.addOnSvc.PriceLineItems(reg.Id, transactionID, subtype, []dto.AddOnRequest{{
+		Code:             model.AddOnCodeGalaDinner,
+		Quantity:         len(accompanyPersons),
+		AccompanyPersons: true,
+	}})
+	if err != nil {
+		return "", err
+	}
+	if err = r.addOnSvc.Reserve(items); err != nil {
+		return "", err
+	}
+	paymentURL, err := r.payAccompanyPersons(reg, accompanyPersons, items, clientIP, transactionID)
+	if err != nil {
+		// the reserved dinners are given back when they cannot be paid for
+		if releaseErr := r.addOnSvc.MarkLineItems(reg.Id, transactionID, model.LineItemStatusFail); releaseErr != nil {
+			log.Printf("Release add-ons of %s failed: %s", transactionID, releaseErr.Error())
+		}
+		return "", err
+	}
+	return paymentURL, nil
+}
+
+// payAccompanyPersons stores the accompanying persons of reserved gala dinner items and starts
+// the payment attempt for them.
+func (r registrationService) payAccompanyPersons(
+	reg *model.Registration, accompanyPersons model.AccompanyPersonList, items model.LineItemList, clientIP, transactionID string,
+) (string, error) {
 	var accompanyPersonsDB []model.AccompanyPersonDB
 	for i := range accompanyPersons {
 		accompanyPersonsDB = append(accompanyPersonsDB, model.AccompanyPersonDB{
@@ -311,34 +776,25 @@ func (r registrationService) RegisterForAccompanyPersons(email string, accompany
 			DateOfBirth:    accompanyPersons[i].DateOfBirth,
 		})
 	}
-	err = r.registrationRepo.SaveAccompanyPersons(accompanyPersonsDB)
+	err := r.registrationRepo.SaveAccompanyPersons(accompanyPersonsDB)
 	if err != nil {
 		return "", err
 	}
 	// Update the in-memory reg object for payment calculation
 	reg.AccompanyPersons = accompanyPersons
-	paymentURL, err := r.generatePaymentURLForAccompanyPersons(reg, accompanyPersons, clientIP, transactionID)
+	// accompanying persons are paid through OnePay, whichever way the registration was paid
+	if !model.PaymentMethod(reg.PaymentMethod).IsOnePay() {
+		reg.PaymentMethod = string(model.DefaultPaymentMethod(reg.Nationality))
+	}
+	paymentURL, transaction, err := r.generatePaymentURLForAccompanyPersons(reg, items, clientIP, transactionID)
 	if err != nil {
 		return "", err
 	}
-	return paymentURL, nil
-}
-
-func DetermineRegistrationPeriod(now time.Time) string {
-	earlyBirdEnd := time.Date(2025, 8, 31, 23, 59, 59, 0, time.UTC)
-	regularEnd := time.Date(2025, 10, 31, 23, 59, 59, 0, time.UTC)
-	onSiteStart := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
-
-	switch {
-	case now.Before(earlyBirdEnd):
-		return string(model.EarlyBird)
-	case now.Before(regularEnd):
-		return string(model.Regular)
-	case now.After(onSiteStart):
-		return string(model.OnSite)
-	default:
-		return string(model.Regular)
+	_, err = r.paymentTransactionRepo.Create(transaction)
+	if err != nil {
+		return "", err
 	}
+	return paymentURL, nil
 }
 
 const charset = "abcdefghijklmnopqrstuvwxyz" +
@@ -353,108 +809,395 @@ func RandomString(length int) string {
 	return string(b)
 }
 
-func (r registrationService) generatePaymentURL(reg *model.Registration, clientIP string) (string, error) {
-	op := r.config.OnePay
-	locale := "en"
-	currency := "VND"
-	// adding AccompanyPersons fee
-	optionUSDFee := reg.RegistrationOption.FeeUSD + float64(len(reg.AccompanyPersons))*model.GalaDinnerOnlyOption.FeeUSD
-	optionVNDFee := reg.RegistrationOption.FeeVND + int64(len(reg.AccompanyPersons))*model.GalaDinnerOnlyOption.FeeVND
+func (r registrationService) generatePaymentURL(reg *model.Registration, merchTxnRef, clientIP string) (string, model.PaymentTransaction, error) {
+	// option fee plus the add-ons, the accompany persons' gala dinners included
+	optionUSDFee, optionVNDFee := reg.Fee()
 
-	usd := int64(optionUSDFee)
-	amount := strconv.FormatInt(usd*RateUSDVND*100, 10)
-	if reg.Nationality == model.NationalityVietNam {
-		locale = "vn"
-		amount = strconv.FormatInt(optionVNDFee*100, 10)
-	}
+	return r.newPayment(registrationPayer(reg), model.OrderTypeRegistration, merchTxnRef, RandomString(16), optionUSDFee, optionVNDFee, clientIP)
+}
 
-	merchantQueryMap := map[string]string{
-		"vpc_Version":     "2",
-		"vpc_Currency":    currency,
-		"vpc_Command":     "pay",
-		"vpc_AccessCode":  op.AccessCode,
-		"vpc_Merchant":    op.MerchantID,
-		"vpc_Locale":      locale,
-		"vpc_ReturnURL":   op.ReturnURL + "/" + reg.Id,
-		"vpc_MerchTxnRef": reg.Id,
-		"vpc_OrderInfo":   fmt.Sprintf("ORDER%s", RandomString(16)),
-		"vpc_Amount":      amount,
-		"vpc_TicketNo":    clientIP,
-		"vpc_CallbackURL": r.config.Server.Host + "/onepay/ipn",
-	}
+func (r registrationService) generatePaymentURLForAccompanyPersons(reg *model.Registration, items model.LineItemList, clientIP, transactionID string) (string, model.PaymentTransaction, error) {
+	optionUSDFee, optionVNDFee := items.Total()
 
-	queryParamSorted := sortParams(merchantQueryMap)
-	stringTohash := generateStringToHash(queryParamSorted)
-	merchantGenSecureHash := generateSecureHash(stringTohash, op.HashCode)
-	merchantQueryMap["vpc_SecureHash"] = merchantGenSecureHash
+	return r.newPayment(registrationPayer(reg), model.OrderTypeAccompanyPerson, transactionID, transactionID, optionUSDFee, optionVNDFee, clientIP)
+}
 
-	params := url.Values{}
-	for key, value := range merchantQueryMap {
-		params.Add(key, value)
-	}
-	requestUrl := op.Endpoint + "?" + params.Encode()
-	return requestUrl, nil
+// payer is who a payment attempt is charged to: a registration or the sponsor of a group.
+type payer struct {
+	registrationID string
+	groupID        string
+	paymentMethod  string
+	nationality    string
 }
 
-func (r registrationService) generatePaymentURLForAccompanyPersons(reg *model.Registration, accompanyPersons model.AccompanyPersonList, clientIP, transactionID string) (string, error) {
-	op := r.config.OnePay
-	locale := "en"
-	currency := "VND"
+func registrationPayer(reg *model.Registration) payer {
+	return payer{registrationID: reg.Id, paymentMethod: reg.PaymentMethod, nationality: reg.Nationality}
+}
 
-	numAccompany := len(accompanyPersons)
-	optionUSDFee := float64(numAccompany) * model.GalaDinnerOnlyOption.FeeUSD
-	optionVNDFee := int64(numAccompany) * model.GalaDinnerOnlyOption.FeeVND
+func groupPayer(group *model.RegistrationGroup) payer {
+	return payer{groupID: group.Id, paymentMethod: group.PaymentMethod, nationality: group.Nationality}
+}
 
-	usd := int64(optionUSDFee)
-	amount := strconv.FormatInt(usd*RateUSDVND*100, 10)
-	if reg.Nationality == model.NationalityVietNam {
+// newPayment builds a payment attempt whose URL can be paid with for the payment link TTL.
+func (r registrationService) newPayment(
+	p payer, orderType model.OrderType, merchTxnRef, orderRef string,
+	feeUSD float64, feeVND int64, clientIP string,
+) (string, model.PaymentTransaction, error) {
+	expiresAt := time.Now().UTC().Add(r.config.Payment.GetLinkTTL())
+	return r.newPaymentUntil(p, orderType, merchTxnRef, orderRef, feeUSD, feeVND, clientIP, expiresAt)
+}
+
+// newPaymentUntil builds the OnePay redirect for a payment attempt that expires at expiresAt and
+// the ledger entry recording it. Vietnamese payers pay the VND price and everyone else the USD
+// price, converted to the currency of the merchant profile of the payer's payment method. The
+// converted amount is signed into the URL, so the rate stored on the ledger entry holds for the
+// life of the URL.
+func (r registrationService) newPaymentUntil(
+	p payer, orderType model.OrderType, merchTxnRef, orderRef string,
+	feeUSD float64, feeVND int64, clientIP string, expiresAt time.Time,
+) (string, model.PaymentTransaction, error) {
+	client := r.onePayClient(p.paymentMethod)
+	locale := "en"
+	if p.nationality == model.NationalityVietNam {
 		locale = "vn"
-		amount = strconv.FormatInt(optionVNDFee*100, 10)
+	}
+	currency := client.Currency()
+	var amount int64
+	var rate float64
+	switch {
+	case currency == CurrencyUSD && p.nationality == model.NationalityVietNam:
+		var err error
+		if rate, err = r.rateProvider.USDToVND(); err != nil {
+			return "", model.PaymentTransaction{}, err
+		}
+		amount = int64(math.Ceil(float64(feeVND) / rate))
+	case currency == CurrencyUSD:
+		// prices are whole dollars, a fee with cents would be charged short
+		if !model.IsWholeUSD(feeUSD) {
+			return "", model.PaymentTransaction{}, errs.ErrInvalidValue.Reform("fee of %.2f USD is not whole dollars", feeUSD)
+		}
+		amount = int64(feeUSD)
+	case p.nationality == model.NationalityVietNam:
+		amount = feeVND
+	default:
+		var err error
+		if rate, err = r.rateProvider.USDToVND(); err != nil {
+			return "", model.PaymentTransaction{}, err
+		}
+		amount = int64(math.Ceil(feeUSD * rate))
 	}
 
-	merchantQueryMap := map[string]string{
-		"vpc_Version":     "2",
-		"vpc_Currency":    currency,
-		"vpc_Command":     "pay",
-		"vpc_AccessCode":  op.AccessCode,
-		"vpc_Merchant":    op.MerchantID,
-		"vpc_Locale":      locale,
-		"vpc_ReturnURL":   op.ReturnURL + "/" + reg.Id,
-		"vpc_MerchTxnRef": transactionID,
-		"vpc_OrderInfo":   fmt.Sprintf("ACCOM%s", transactionID),
-		"vpc_Amount":      amount,
-		"vpc_TicketNo":    clientIP,
-		"vpc_CallbackURL": r.config.Server.Host + "/onepay/ipn",
+	request := onepay.PaymentRequest{
+		MerchTxnRef: merchTxnRef,
+		OrderInfo:   fmt.Sprintf("%s%s", orderType, orderRef),
+		Amount:      amount,
+		Currency:    currency,
+		Locale:      locale,
+		ReturnURL:   r.config.Server.GetPublicURL() + "/onepay/return",
+		CallbackURL: r.config.Server.GetPublicURL() + "/onepay/ipn",
+		ClientIP:    clientIP,
+		ExpiresAt:   expiresAt,
+	}
+	requestUrl, err := client.BuildPaymentURL(request)
+	if err != nil {
+		return "", model.PaymentTransaction{}, err
+	}
+	transaction := model.PaymentTransaction{
+		RegistrationID: p.registrationID,
+		GroupID:        p.groupID,
+		MerchTxnRef:    request.MerchTxnRef,
+		OrderInfo:      request.OrderInfo,
+		OrderType:      string(orderType),
+		PaymentMethod:  p.paymentMethod,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		ExchangeRate:   rate,
+		ClientIP:       clientIP,
+		ExpiresAt:      &expiresAt,
+		Status:         string(model.PaymentTransactionStatusPending),
+	}
+	return requestUrl, transaction, nil
+}
+
+// RenewPaymentURL starts a new payment attempt for a registration that is not paid yet.
+// The registration fee is looked up again for the current registration period, so an old
+// registration is charged the price of the period it is paid in. An empty URL is returned
+// when nothing is left to pay.
+func (r registrationService) RenewPaymentURL(registrationID, clientIP string) (string, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return "", err
+	}
+	if reg == nil {
+		return "", errs.ErrNotFound.Reform("registration not found")
+	}
+	if reg.PaymentStatus != string(model.PaymentStatusPending) && reg.PaymentStatus != string(model.PaymentStatusFail) {
+		return "", errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	if reg.GroupID != "" {
+		return "", errs.ErrBadRequest.Reform("registration is paid by its sponsor group")
+	}
+	if reg.PaymentMethod != "" && !model.PaymentMethod(reg.PaymentMethod).IsOnePay() {
+		return "", errs.ErrBadRequest.Reform("registration is paid by %s", reg.PaymentMethod)
+	}
+	if reg.PaymentMethod == "" {
+		reg.PaymentMethod = string(model.DefaultPaymentMethod(reg.Nationality))
+	}
+
+	period, err := r.periodSvc.CurrentPeriod()
+	if err != nil {
+		return "", err
+	}
+	if err := r.reprice(reg, period); err != nil {
+		return "", err
+	}
+	if err := r.renewSeat(reg); err != nil {
+		return "", err
+	}
+	if nothingToPay(*reg) {
+		return "", r.completeWithoutPayment(reg, clientIP)
 	}
 
-	queryParamSorted := sortParams(merchantQueryMap)
-	stringTohash := generateStringToHash(queryParamSorted)
-	merchantGenSecureHash := generateSecureHash(stringTohash, op.HashCode)
-	merchantQueryMap["vpc_SecureHash"] = merchantGenSecureHash
+	// OnePay requires a new vpc_MerchTxnRef for every attempt, the ledger links it to the registration.
+	paymentURL, transaction, err := r.generatePaymentURL(reg, RandomString(16), clientIP)
+	if err != nil {
+		return "", err
+	}
+	_, err = r.paymentTransactionRepo.Create(transaction)
+	if err != nil {
+		return "", err
+	}
+	return paymentURL, nil
+}
 
-	params := url.Values{}
-	for key, value := range merchantQueryMap {
-		params.Add(key, value)
+// reprice moves an unpaid registration to the prices of period: its option, its pending add-ons
+// and the promo code discount, which must still be valid.
+func (r registrationService) reprice(reg *model.Registration, period *model.Period) error {
+	if reg.RegistrationOption.Subtype != "" {
+		option, err := r.registrationOptionsRepo.Find(model.RegistrationOptionFilter{
+			Category: reg.RegistrationOption.Category,
+			Subtype:  period.Name,
+		})
+		if err != nil {
+			return errs.ErrNotFound.Reform("option not found")
+		}
+		if option.Id != reg.RegistrationOptionID {
+			if err := r.registrationRepo.UpdateRegistrationOption(reg.Id, option.Id); err != nil {
+				return err
+			}
+			reg.RegistrationOptionID = option.Id
+			reg.RegistrationOption = *option
+		}
 	}
-	requestUrl := op.Endpoint + "?" + params.Encode()
-	return requestUrl, nil
+	var err error
+	reg.LineItems, err = r.addOnSvc.Reprice(reg.LineItems, period.Name)
+	if err != nil {
+		return err
+	}
+	if reg.PromoCodeID != "" {
+		if err := r.applyPromoCode(reg, reg.PromoCode); err != nil {
+			return err
+		}
+		if err := r.promoCodeSvc.Redeem(reg.PromoCodeID, *reg); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r registrationService) GetRegistrations(startTime, endTime time.Time) ([]*model.Registration, error) {
 	return r.registrationRepo.GetRegistrations(startTime, endTime)
 }
 
+// ReconcilePendingPayments asks OnePay about attempts whose IPN never arrived and applies the
+// answer through the IPN code path. Attempts OnePay has never seen are abandoned once they are old enough.
+func (r registrationService) ReconcilePendingPayments() error {
+	rc := r.config.Reconcile
+	now := time.Now().UTC()
+	transactions, err := r.paymentTransactionRepo.ListPending(now.Add(-rc.GetPendingAfter()), rc.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, transaction := range transactions {
+		result, err := r.onePayClient(transaction.PaymentMethod).QueryDR(transaction.MerchTxnRef)
+		if err != nil {
+			log.Printf("QueryDR failed for %s: %s", transaction.MerchTxnRef, err.Error())
+			continue
+		}
+		if !result.Exists {
+			// a link still valid, e.g. one held for a waitlist invitation, may be paid yet
+			if transaction.CreatedAt.Before(now.Add(-rc.GetAbandonAfter())) &&
+				(transaction.ExpiresAt == nil || transaction.IsExpired(now)) {
+				log.Printf("Payment %s abandoned", transaction.MerchTxnRef)
+				_, err = r.paymentTransactionRepo.CompletePending(transaction.Id, model.PaymentTransactionResult{
+					Status:  string(model.PaymentTransactionStatusAbandoned),
+					Message: "transaction not found at OnePay",
+				})
+				if err != nil {
+					log.Printf("Abandon payment %s failed: %s", transaction.MerchTxnRef, err.Error())
+				}
+			}
+			continue
+		}
+		if onepay.IsPending(result.ResponseCode) {
+			continue
+		}
+		// QueryDR answers are keyed by vpc_MerchTxnRef only, take the order info from the ledger.
+		result.OrderInfo = transaction.OrderInfo
+		log.Printf("Reconciled payment %s with code %s", transaction.MerchTxnRef, result.ResponseCode)
+		if err := r.applyPaymentResult(result.PaymentResult); err != nil {
+			log.Printf("Apply reconciled payment %s failed: %s", transaction.MerchTxnRef, err.Error())
+		}
+	}
+	return nil
+}
+
+func (r registrationService) GetPaymentTransactions(registrationID string) ([]*model.PaymentTransaction, error) {
+	return r.paymentTransactionRepo.ListByRegistrationID(registrationID)
+}
+
+// Refund gives back part or all of a successful payment through OnePay and moves the
+// registration to refunded once everything it paid has been given back.
+func (r registrationService) Refund(registrationID string, request dto.RefundRequest, operator string) (*model.Refund, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if reg.PaymentStatus != string(model.PaymentStatusDone) && reg.PaymentStatus != string(model.PaymentStatusPartiallyRefunded) {
+		return nil, errs.ErrBadRequest.Reform("registration has no payment to refund")
+	}
+	transactions, err := r.paymentTransactionRepo.ListByRegistrationID(registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// refunded is what each successful payment has been refunded, in the payment's currency
+	var target *model.PaymentTransaction
+	var paid []*model.PaymentTransaction
+	refunded := map[string]int64{}
+	for _, transaction := range transactions {
+		if transaction.Status != string(model.PaymentTransactionStatusSuccess) {
+			continue
+		}
+		if refunded[transaction.Id], err = r.refundRepo.SumSucceededByTransaction(transaction.Id); err != nil {
+			return nil, err
+		}
+		paid = append(paid, transaction)
+		if target != nil {
+			continue
+		}
+		if transaction.Id == request.PaymentTransactionID ||
+			(request.PaymentTransactionID == "" && transaction.OrderType == string(model.OrderTypeRegistration)) {
+			target = transaction
+		}
+	}
+	if target == nil {
+		return nil, errs.ErrNotFound.Reform("successful payment transaction not found")
+	}
+	if !model.PaymentMethod(target.PaymentMethod).IsOnePay() {
+		return nil, errs.ErrBadRequest.Reform("payments received by %s are refunded outside OnePay", target.PaymentMethod)
+	}
+	// a pending refund may still go through, so its amount cannot be refunded again
+	pending, err := r.refundRepo.SumPendingByTransaction(target.Id)
+	if err != nil {
+		return nil, err
+	}
+	refundable := target.Amount - refunded[target.Id] - pending
+	amount := request.Amount
+	if amount == 0 {
+		amount = refundable
+	}
+	if refundable <= 0 && pending > 0 {
+		return nil, errs.ErrBadRequest.Reform("a refund of %d %s is still waiting for OnePay", pending, target.Currency)
+	}
+	if amount <= 0 || amount > refundable {
+		return nil, errs.ErrInvalidArgument.Reform("refund amount must be between 1 and %d %s", refundable, target.Currency)
+	}
+
+	rf, err := r.refundRepo.Create(model.Refund{
+		RegistrationID:       registrationID,
+		PaymentTransactionID: target.Id,
+		MerchTxnRef:          fmt.Sprintf("RF%s", RandomString(16)),
+		OrgMerchTxnRef:       target.MerchTxnRef,
+		Amount:               amount,
+		Currency:             target.Currency,
+		Reason:               request.Reason,
+		RequestedBy:          operator,
+		Status:               string(model.RefundStatusPending),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The refund stays pending when OnePay cannot be reached, its outcome is unknown.
+	result, err := r.onePayClient(target.PaymentMethod).Refund(onepay.RefundRequest{
+		MerchTxnRef:    rf.MerchTxnRef,
+		OrgMerchTxnRef: rf.OrgMerchTxnRef,
+		Amount:         rf.Amount,
+		Operator:       operator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rf.Status = string(model.RefundStatusFail)
+	if result.Succeeded() {
+		rf.Status = string(model.RefundStatusSuccess)
+	}
+	rf.ResponseCode = result.ResponseCode
+	rf.Message = result.Message
+	rf.RawQuery = result.RawQuery
+	err = r.refundRepo.UpdateResult(rf.Id, model.PaymentTransactionResult{
+		Status:       rf.Status,
+		ResponseCode: rf.ResponseCode,
+		Message:      rf.Message,
+		RawQuery:     rf.RawQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rf.Status != string(model.RefundStatusSuccess) {
+		return nil, errs.ErrOtherService.Reform("OnePay refund failed: %s", rf.Message)
+	}
+
+	// the registration is refunded once every payment is, each compared in its own currency
+	refunded[target.Id] += amount
+	status := model.PaymentStatusRefunded
+	for _, transaction := range paid {
+		if refunded[transaction.Id] < transaction.Amount {
+			status = model.PaymentStatusPartiallyRefunded
+			break
+		}
+	}
+	err = r.registrationRepo.UpdatePaymentStatus(registrationID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
 var registrationServiceInstance RegistrationService
 var registrationServiceOnce sync.Once
 
 func GetRegistrationServiceInstance(
 	registrationRepo repository.RegistrationRepository,
 	registrationOptionsRepo repository.RegistrationOptionRepository,
+	paymentTransactionRepo repository.PaymentTransactionRepository,
+	refundRepo repository.RefundRepository,
+	registrationGroupRepo repository.RegistrationGroupRepository,
+	registrationChangeRepo repository.RegistrationChangeRepository,
+	auditLogRepo repository.AuditLogRepository,
+	categoryCapacityRepo repository.CategoryCapacityRepository,
+	waitlistRepo repository.WaitlistRepository,
+	onePay map[model.PaymentMethod]*onepay.Client,
+	rateProvider RateProvider,
+	periodSvc PeriodService,
+	addOnSvc AddOnService,
+	promoCodeSvc PromoCodeService,
 	config *config.Config,
 ) RegistrationService {
 	registrationServiceOnce.Do(func() {
 		registrationServiceInstance = NewRegistrationService(
-			registrationRepo, registrationOptionsRepo, config,
+			registrationRepo, registrationOptionsRepo, paymentTransactionRepo, refundRepo, registrationGroupRepo,
+			registrationChangeRepo, auditLogRepo, categoryCapacityRepo, waitlistRepo, onePay, rateProvider, periodSvc, addOnSvc, promoCodeSvc, config,
 		)
 	})
 	return registrationServiceInstance
@@ -463,11 +1206,35 @@ func GetRegistrationServiceInstance(
 func NewRegistrationService(
 	registrationRepo repository.RegistrationRepository,
 	registrationOptionsRepo repository.RegistrationOptionRepository,
+	paymentTransactionRepo repository.PaymentTransactionRepository,
+	refundRepo repository.RefundRepository,
+	registrationGroupRepo repository.RegistrationGroupRepository,
+	registrationChangeRepo repository.RegistrationChangeRepository,
+	auditLogRepo repository.AuditLogRepository,
+	categoryCapacityRepo repository.CategoryCapacityRepository,
+	waitlistRepo repository.WaitlistRepository,
+	onePay map[model.PaymentMethod]*onepay.Client,
+	rateProvider RateProvider,
+	periodSvc PeriodService,
+	addOnSvc AddOnService,
+	promoCodeSvc PromoCodeService,
 	config *config.Config,
 ) RegistrationService {
 	return &registrationService{
 		registrationRepo:        registrationRepo,
 		registrationOptionsRepo: registrationOptionsRepo,
+		paymentTransactionRepo:  paymentTransactionRepo,
+		refundRepo:              refundRepo,
+		registrationGroupRepo:   registrationGroupRepo,
+		registrationChangeRepo:  registrationChangeRepo,
+		auditLogRepo:            auditLogRepo,
+		categoryCapacityRepo:    categoryCapacityRepo,
+		waitlistRepo:            waitlistRepo,
+		onePay:                  onePay,
+		rateProvider:            rateProvider,
+		periodSvc:               periodSvc,
+		addOnSvc:                addOnSvc,
+		promoCodeSvc:            promoCodeSvc,
 		config:                  config,
 	}
 }
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// RequestCancellation records an attendee's request to cancel their paid registration with the
+// refund the policy grants today. The registration is cancelled once an admin approves it.
+func (r registrationService) RequestCancellation(registrationID string, request dto.CancellationRequest) (*model.RegistrationChange, error) {
+	reg, err := r.changeableRegistration(registrationID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	change := model.RegistrationChange{
+		RegistrationID: reg.Id,
+		Type:           model.ChangeTypeCancellation,
+		Status:         model.ChangeStatusPending,
+		Reason:         request.Reason,
+		RefundPercent:  r.config.Cancellation.GetRefundPercent(time.Now().UTC()),
+	}
+	payment, refundable, err := r.registrationPayment(reg.Id)
+	if err != nil {
+		return nil, err
+	}
+	// registrations paid by a sponsor group have no payment of their own to refund
+	if payment != nil {
+		change.RefundAmount = int64(math.Floor(float64(refundable) * change.RefundPercent / 100))
+		change.Currency = payment.Currency
+	}
+	created, err := r.registrationChangeRepo.Create(change)
+	if err != nil {
+		return nil, err
+	}
+	r.audit(reg.Id, model.AuditActionCancellationRequested, reg.Email,
+		fmt.Sprintf("refund %d %s (%g%%): %s", change.RefundAmount, change.Currency, change.RefundPercent, request.Reason))
+	go r.sendNotice(reg.Email, reg.FirstName, "Cancellation request received",
+		"We received your request to cancel your registration. We will let you know once it has been reviewed.")
+	return created, nil
+}
+
+// RequestSubstitution records an attendee's request to hand their paid registration over to someone
+// else. The registration changes hands once an admin approves it.
+func (r registrationService) RequestSubstitution(registrationID string, request dto.SubstitutionRequest) (*model.RegistrationChange, error) {
+	reg, err := r.changeableRegistration(registrationID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	substitute := request.Substitute
+	if strings.EqualFold(strings.TrimSpace(substitute.Email), reg.Email) {
+		return nil, errs.ErrInvalidArgument.Reform("the substitute needs an email of their own")
+	}
+	if _, err := r.substituteRegistration(substitute.Email); err != nil {
+		return nil, err
+	}
+	change := model.RegistrationChange{
+		RegistrationID: reg.Id,
+		Type:           model.ChangeTypeSubstitution,
+		Status:         model.ChangeStatusPending,
+		Reason:         request.Reason,
+		Substitute: model.Attendee{
+			DoctorateDegree: substitute.DoctorateDegree,
+			FirstName:       substitute.FirstName,
+			MiddleName:      substitute.MiddleName,
+			LastName:        substitute.LastName,
+			DateOfBirth:     substitute.DateOfBirth,
+			Institution:     substitute.Institution,
+			Email:           strings.TrimSpace(substitute.Email),
+			PhoneNumber:     substitute.PhoneNumber,
+		},
+	}
+	created, err := r.registrationChangeRepo.Create(change)
+	if err != nil {
+		return nil, err
+	}
+	r.audit(reg.Id, model.AuditActionSubstitutionRequested, reg.Email,
+		fmt.Sprintf("to %s: %s", change.Substitute.Email, request.Reason))
+	go r.sendNotice(reg.Email, reg.FirstName, "Substitution request received",
+		fmt.Sprintf("We received your request to transfer your registration to %s. We will let you know once it has been reviewed.",
+			change.Substitute.Email))
+	return created, nil
+}
+
+// changeableRegistration returns a paid registration its attendee may ask to change:
+// email must be the registration's and no other request may be waiting for a decision.
+func (r registrationService) changeableRegistration(registrationID, email string) (*model.Registration, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(reg.Email, strings.TrimSpace(email)) {
+		return nil, errs.ErrBadRequest.Reform("email does not match the registration")
+	}
+	if !isPaid(reg) {
+		return nil, errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	changes, err := r.registrationChangeRepo.ListByRegistrationID(reg.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if change.Status == model.ChangeStatusPending {
+			return nil, errs.ErrBadRequest.Reform("a %s request is already pending", change.Type)
+		}
+	}
+	return reg, nil
+}
+
+// substituteRegistration returns the unpaid registration a substitute made themselves, which gives way
+// to the one handed over to them. An email with any other registration cannot take one over.
+func (r registrationService) substituteRegistration(email string) (*model.Registration, error) {
+	reg, err := r.registrationRepo.GetByEmail(strings.TrimSpace(email))
+	if err != nil || reg == nil {
+		return nil, err
+	}
+	if reg.GroupID != "" ||
+		(reg.PaymentStatus != string(model.PaymentStatusPending) && reg.PaymentStatus != string(model.PaymentStatusFail)) {
+		return nil, errs.ErrInvalidArgument.Reform("%s is already registered", email)
+	}
+	return reg, nil
+}
+
+func isPaid(reg *model.Registration) bool {
+	return reg.PaymentStatus == string(model.PaymentStatusDone) ||
+		reg.PaymentStatus == string(model.PaymentStatusPartiallyRefunded)
+}
+
+// registrationPayment returns the successful payment of a registration and how much of it is
+// neither refunded nor being refunded, nil when the registration was not paid on its own.
+func (r registrationService) registrationPayment(registrationID string) (*model.PaymentTransaction, int64, error) {
+	transactions, err := r.paymentTransactionRepo.ListByRegistrationID(registrationID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, transaction := range transactions {
+		if transaction.Status != string(model.PaymentTransactionStatusSuccess) ||
+			transaction.OrderType != string(model.OrderTypeRegistration) {
+			continue
+		}
+		refunded, err := r.refundRepo.SumSucceededByTransaction(transaction.Id)
+		if err != nil {
+			return nil, 0, err
+		}
+		pending, err := r.refundRepo.SumPendingByTransaction(transaction.Id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return transaction, transaction.Amount - refunded - pending, nil
+	}
+	return nil, 0, nil
+}
+
+func (r registrationService) ListRegistrationChanges(registrationID string) ([]*model.RegistrationChange, error) {
+	return r.registrationChangeRepo.ListByRegistrationID(registrationID)
+}
+
+func (r registrationService) ListChanges(status string) ([]*model.RegistrationChange, error) {
+	return r.registrationChangeRepo.List(status)
+}
+
+func (r registrationService) GetAuditLog(registrationID string) ([]*model.AuditLog, error) {
+	return r.auditLogRepo.ListByRegistrationID(registrationID)
+}
+
+// ApproveChange applies a pending request: a cancellation is refunded and frees its add-ons,
+// a substitution hands the registration over with a new ticket. The change is claimed first so
+// it is applied once, and released again when it cannot be applied.
+func (r registrationService) ApproveChange(ID string, request dto.DecideChangeRequest, operator string) (*model.RegistrationChange, error) {
+	change, reg, err := r.pendingChange(ID)
+	if err != nil {
+		return nil, err
+	}
+	if change.Type == model.ChangeTypeSubstitution {
+		if _, err := r.substituteRegistration(change.Substitute.Email); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.claimChange(change, model.ChangeStatusApproved, request.Note, operator); err != nil {
+		return nil, err
+	}
+	if change.Type == model.ChangeTypeCancellation {
+		err = r.cancelRegistration(reg, change, request, operator)
+	} else {
+		err = r.substituteAttendee(reg, change, operator)
+	}
+	if err != nil {
+		if resetErr := r.registrationChangeRepo.ResetPending(change.Id); resetErr != nil {
+			log.Printf("Reset registration change %s failed: %s", change.Id, resetErr.Error())
+		}
+		return nil, err
+	}
+	r.audit(reg.Id, model.AuditActionChangeApproved, operator, fmt.Sprintf("%s %s: %s", change.Type, change.Id, request.Note))
+	return r.registrationChangeRepo.Get(ID)
+}
+
+// RejectChange closes a pending request without changing the registration.
+func (r registrationService) RejectChange(ID string, request dto.DecideChangeRequest, operator string) (*model.RegistrationChange, error) {
+	change, reg, err := r.pendingChange(ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.claimChange(change, model.ChangeStatusRejected, request.Note, operator); err != nil {
+		return nil, err
+	}
+	r.audit(reg.Id, model.AuditActionChangeRejected, operator, fmt.Sprintf("%s %s: %s", change.Type, change.Id, request.Note))
+	body := fmt.Sprintf("Your %s request has been declined.", change.Type)
+	if request.Note != "" {
+		body += " " + request.Note
+	}
+	go r.sendNotice(reg.Email, reg.FirstName, fmt.Sprintf("Your %s request", change.Type), body)
+	return r.registrationChangeRepo.Get(ID)
+}
+
+func (r registrationService) pendingChange(ID string) (*model.RegistrationChange, *model.Registration, error) {
+	change, err := r.registrationChangeRepo.Get(ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if change.Status != model.ChangeStatusPending {
+		return nil, nil, errs.ErrBadRequest.Reform("%s request is already %s", change.Type, change.Status)
+	}
+	reg, err := r.registrationRepo.GetRegistration(change.RegistrationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return change, reg, nil
+}
+
+func (r registrationService) claimChange(change *model.RegistrationChange, status model.ChangeStatus, note, operator string) error {
+	change.Status = status
+	change.DecidedBy = operator
+	change.DecisionNote = note
+	claimed, err := r.registrationChangeRepo.Decide(*change)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errs.ErrBadRequest.Reform("%s request has already been decided", change.Type)
+	}
+	return nil
+}
+
+// cancelRegistration refunds a cancelled registration through OnePay, or records the amount to
+// refund by hand when it was paid outside OnePay, and releases its add-ons.
+func (r registrationService) cancelRegistration(
+	reg *model.Registration, change *model.RegistrationChange, request dto.DecideChangeRequest, operator string,
+) error {
+	if !isPaid(reg) {
+		return errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	amount := change.RefundAmount
+	if request.RefundAmount != nil {
+		amount = *request.RefundAmount
+	}
+	// a refund made by an approval that failed later is not made again
+	if amount > 0 && change.RefundID == "" {
+		payment, refundable, err := r.registrationPayment(reg.Id)
+		if err != nil {
+			return err
+		}
+		if payment == nil {
+			return errs.ErrBadRequest.Reform("registration has no payment to refund")
+		}
+		if amount > refundable {
+			return errs.ErrBadRequest.Reform("refund amount exceeds the refundable %d %s", refundable, payment.Currency)
+		}
+		refundID := ""
+		if model.PaymentMethod(payment.PaymentMethod).IsOnePay() {
+			refund, err := r.Refund(reg.Id, dto.RefundRequest{
+				PaymentTransactionID: payment.Id,
+				Amount:               amount,
+				Reason:               fmt.Sprintf("cancellation %s", change.Id),
+			}, operator)
+			if err != nil {
+				return err
+			}
+			refundID = refund.Id
+		}
+		if err := r.registrationChangeRepo.SetRefund(change.Id, refundID, amount); err != nil {
+			return err
+		}
+		change.Currency = payment.Currency
+	}
+	if err := r.addOnSvc.CancelLineItems(reg.Id); err != nil {
+		return err
+	}
+	if err := r.registrationRepo.UpdatePaymentStatus(reg.Id, string(model.PaymentStatusCancelled)); err != nil {
+		return err
+	}
+	r.audit(reg.Id, model.AuditActionCancelled, operator, fmt.Sprintf("refund %d %s", amount, change.Currency))
+
+	body := "Your registration has been cancelled and its QR code is no longer valid."
+	if amount > 0 {
+		body += fmt.Sprintf(" %d %s will be refunded to you.", amount, change.Currency)
+	}
+	go r.sendNotice(reg.Email, reg.FirstName, "Registration cancelled", body)
+	return nil
+}
+
+// substituteAttendee hands a registration over to the substitute of a change and sends them a new
+// ticket; the QR code of the previous attendee stops matching the registration.
+func (r registrationService) substituteAttendee(reg *model.Registration, change *model.RegistrationChange, operator string) error {
+	if !isPaid(reg) {
+		return errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	oldReg, err := r.substituteRegistration(change.Substitute.Email)
+	if err != nil {
+		return err
+	}
+	if oldReg != nil {
+		if err := r.registrationRepo.Remove(oldReg.Id); err != nil {
+			return err
+		}
+	}
+	if err := r.registrationRepo.UpdateAttendee(reg.Id, change.Substitute, RandomString(16)); err != nil {
+		return err
+	}
+	r.audit(reg.Id, model.AuditActionSubstituted, operator, fmt.Sprintf("from %s to %s", reg.Email, change.Substitute.Email))
+
+	updated, err := r.registrationRepo.GetRegistration(reg.Id)
+	if err != nil {
+		return err
+	}
+	go r.sendConfirmationEmail(updated)
+	go r.sendNotice(reg.Email, reg.FirstName, "Registration transferred",
+		fmt.Sprintf("Your registration has been transferred to %s %s. Your QR code is no longer valid.",
+			change.Substitute.FirstName, change.Substitute.LastName))
+	return nil
+}
+
+// audit records a change to a registration. A failure is logged, the change it records is made already.
+func (r registrationService) audit(registrationID string, action model.AuditAction, actor, detail string) {
+	err := r.auditLogRepo.Create(model.AuditLog{
+		RegistrationID: registrationID,
+		Action:         action,
+		Actor:          actor,
+		Detail:         detail,
+	})
+	if err != nil {
+		log.Printf("Audit %s of %s failed: %s", action, registrationID, err.Error())
+	}
+}
+
+func (r registrationService) sendNotice(email, name, subject, body string) {
+	if err := SendNoticeEmail(email, name, subject, body, r.config); err != nil {
+		log.Printf("Send notice to %s failed: %s", email, err.Error())
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/onepay"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RegisterGroup registers every attendee of a sponsor and returns one payment URL for all of them.
+// The attendees are registered as if each had registered alone, so their options, add-ons and
+// promo codes are priced the same way; nothing is stored unless every attendee can be registered,
+// and the unpaid registrations the attendees made themselves are put back when they cannot.
+func (r registrationService) RegisterGroup(request dto.GroupRegistrationRequest, clientIP string) (*dto.GroupRegistrationResponse, error) {
+	group := model.RegistrationGroup{
+		Sponsor:       request.Sponsor,
+		ContactName:   request.ContactName,
+		ContactEmail:  request.ContactEmail,
+		ContactPhone:  request.ContactPhone,
+		Nationality:   request.Nationality,
+		PaymentMethod: request.PaymentMethod,
+		PaymentStatus: string(model.PaymentStatusPending),
+	}
+	if group.PaymentMethod == "" {
+		group.PaymentMethod = string(model.DefaultPaymentMethod(group.Nationality))
+	}
+	group.Id = uuid.New().String()
+
+	emails := map[string]bool{}
+	var replaced []model.Registration
+	for _, attendee := range request.Attendees {
+		email := strings.ToLower(strings.TrimSpace(attendee.Email))
+		if emails[email] {
+			return nil, errs.ErrInvalidArgument.Reform("attendee %s is listed twice", attendee.Email)
+		}
+		emails[email] = true
+		oldReg, err := r.registrationRepo.GetByEmail(attendee.Email)
+		if err != nil {
+			return nil, err
+		}
+		if oldReg != nil {
+			if oldReg.PaymentStatus != string(model.PaymentStatusPending) && oldReg.PaymentStatus != string(model.PaymentStatusFail) {
+				return nil, errs.ErrInvalidArgument.Reform("attendee %s is already registered", attendee.Email)
+			}
+			if oldReg.GroupID != "" {
+				return nil, errs.ErrInvalidArgument.Reform("attendee %s is already registered by a sponsor group", attendee.Email)
+			}
+			replaced = append(replaced, *oldReg)
+		}
+
+		attendee.Sponsor = request.Sponsor
+		attendee.PaymentMethod = group.PaymentMethod
+		reg, err := r.setupRegistration(attendee)
+		if err != nil {
+			return nil, attendeeError(attendee.Email, err)
+		}
+		reg.GroupID = group.Id
+		group.Registrations = append(group.Registrations, reg)
+	}
+
+	// the unpaid registrations the attendees made themselves hold their emails, so they are
+	// removed first and restored if the group cannot be stored
+	for i, oldReg := range replaced {
+		if err := r.registrationRepo.Remove(oldReg.Id); err != nil {
+			r.restoreRegistrations(replaced[:i])
+			return nil, err
+		}
+	}
+	if _, err := r.registrationGroupRepo.Create(group); err != nil {
+		r.restoreRegistrations(replaced)
+		return nil, err
+	}
+	if err := r.createGroupRegistrations(group); err != nil {
+		r.discardGroup(group.Id)
+		r.restoreRegistrations(replaced)
+		return nil, err
+	}
+
+	response := &dto.GroupRegistrationResponse{
+		GroupID:       group.Id,
+		PaymentStatus: string(model.PaymentStatusPending),
+	}
+	for _, reg := range group.Registrations {
+		response.RegistrationIDs = append(response.RegistrationIDs, reg.Id)
+	}
+	paymentURL, err := r.startGroupPayment(&group, clientIP)
+	if err != nil {
+		// nobody can pay it, so its seats, add-ons and promo codes are given back
+		for _, reg := range group.Registrations {
+			r.discardRegistration(reg.Id)
+		}
+		r.discardGroup(group.Id)
+		r.restoreRegistrations(replaced)
+		return nil, err
+	}
+	if paymentURL == "" {
+		response.PaymentStatus = string(model.PaymentStatusDone)
+	}
+	response.PaymentURL = paymentURL
+	return response, nil
+}
+
+// createGroupRegistrations stores the registrations of a group with their add-ons and promo codes,
+// removing the ones already stored if one of them fails.
+func (r registrationService) createGroupRegistrations(group model.RegistrationGroup) error {
+	var created []string
+	for _, reg := range group.Registrations {
+		err := r.createRegistration(reg)
+		if err == nil {
+			created = append(created, reg.Id)
+			continue
+		}
+		for _, ID := range created {
+			r.discardRegistration(ID)
+		}
+		return attendeeError(reg.Email, err)
+	}
+	return nil
+}
+
+// discardGroup removes a registration group that could not be registered or paid for.
+func (r registrationService) discardGroup(ID string) {
+	if err := r.registrationGroupRepo.Remove(ID); err != nil {
+		log.Printf("Remove registration group %s failed: %s", ID, err.Error())
+	}
+}
+
+// restoreRegistrations puts back registrations removed to make way for ones that could not be stored.
+func (r registrationService) restoreRegistrations(regs []model.Registration) {
+	for _, reg := range regs {
+		if err := r.registrationRepo.Restore(reg); err != nil {
+			log.Printf("Restore registration %s failed: %s", reg.Id, err.Error())
+		}
+	}
+}
+
+// createRegistration stores a registration and reserves its seat, add-ons and promo code.
+// The seats freed while attendees are on the waitlist are kept for them.
+func (r registrationService) createRegistration(reg model.Registration) error {
+	heldSeats, err := r.waitlistRepo.CountWaiting(reg.RegistrationOption.Category)
+	if err != nil {
+		return err
+	}
+	return r.storeRegistration(reg, heldSeats)
+}
+
+// storeRegistration stores a registration if its category has a seat left besides heldSeats
+// and reserves its add-ons and promo code.
+func (r registrationService) storeRegistration(reg model.Registration, heldSeats int) error {
+	if _, err := r.registrationRepo.Create(reg, heldSeats); err != nil {
+		return err
+	}
+	if err := r.addOnSvc.Reserve(reg.LineItems); err != nil {
+		r.discardRegistration(reg.Id)
+		return err
+	}
+	if reg.PromoCodeID != "" {
+		if err := r.promoCodeSvc.Redeem(reg.PromoCodeID, reg); err != nil {
+			r.discardRegistration(reg.Id)
+			return err
+		}
+	}
+	return nil
+}
+
+// attendeeError names the attendee an error of a group registration is about.
+func attendeeError(email string, err error) error {
+	if appErr, ok := err.(errs.AppError); ok {
+		return appErr.Reform("%s: %s", email, appErr.Message)
+	}
+	return err
+}
+
+func (r registrationService) GetGroup(ID string) (*model.RegistrationGroup, error) {
+	return r.registrationGroupRepo.Get(ID)
+}
+
+// RenewGroupPaymentURL starts a new payment attempt for the unpaid registrations of a group,
+// at the prices of the current registration period.
+func (r registrationService) RenewGroupPaymentURL(groupID, clientIP string) (string, error) {
+	group, err := r.registrationGroupRepo.Get(groupID)
+	if err != nil {
+		return "", err
+	}
+	if group.PaymentStatus != string(model.PaymentStatusPending) && group.PaymentStatus != string(model.PaymentStatusFail) {
+		return "", errs.ErrBadRequest.Reform("registration group is %s", group.PaymentStatus)
+	}
+	period, err := r.periodSvc.CurrentPeriod()
+	if err != nil {
+		return "", err
+	}
+	for i := range group.Registrations {
+		if group.Registrations[i].PaymentStatus == string(model.PaymentStatusDone) {
+			continue
+		}
+		if err := r.reprice(&group.Registrations[i], period); err != nil {
+			return "", attendeeError(group.Registrations[i].Email, err)
+		}
+		if err := r.renewSeat(&group.Registrations[i]); err != nil {
+			return "", attendeeError(group.Registrations[i].Email, err)
+		}
+	}
+	return r.startGroupPayment(group, clientIP)
+}
+
+// startGroupPayment records a payment attempt for the unpaid registrations of a group and returns
+// its URL. When nothing is left to pay they are marked paid and the URL is empty.
+func (r registrationService) startGroupPayment(group *model.RegistrationGroup, clientIP string) (string, error) {
+	feeUSD, feeVND := group.Fee()
+	if chargesNothing(group.Nationality, feeUSD, feeVND) {
+		for i := range group.Registrations {
+			if group.Registrations[i].PaymentStatus == string(model.PaymentStatusDone) {
+				continue
+			}
+			if err := r.completeWithoutPayment(&group.Registrations[i], clientIP); err != nil {
+				return "", err
+			}
+		}
+		return "", r.registrationGroupRepo.UpdatePaymentStatus(group.Id, string(model.PaymentStatusDone))
+	}
+	orderRef := RandomString(16)
+	paymentURL, transaction, err := r.newPayment(groupPayer(group), model.OrderTypeGroup, RandomString(16), orderRef, feeUSD, feeVND, clientIP)
+	if err != nil {
+		return "", err
+	}
+	if _, err = r.paymentTransactionRepo.Create(transaction); err != nil {
+		return "", err
+	}
+	return paymentURL, nil
+}
+
+// applyGroupPayment applies the result of a group payment to every unpaid registration of the
+// group and returns the confirmation emails, one for each attendee, to send once it is stored.
+func (r registrationService) applyGroupPayment(groupID, txnCode, message string) (func(), error) {
+	group, err := r.registrationGroupRepo.Get(groupID)
+	if err != nil {
+		return nil, err
+	}
+	var sendEmails []func()
+	for _, reg := range group.Registrations {
+		if reg.PaymentStatus == string(model.PaymentStatusDone) {
+			continue
+		}
+		sendEmail, err := r.applyRegistrationPayment(reg.Id, txnCode, message)
+		if err != nil {
+			return nil, err
+		}
+		if sendEmail != nil {
+			sendEmails = append(sendEmails, sendEmail)
+		}
+	}
+	status := model.PaymentStatusDone
+	if txnCode != onepay.ResponseCodeSuccess {
+		if group.PaymentStatus == string(model.PaymentStatusDone) {
+			return nil, nil
+		}
+		status = model.PaymentStatusFail
+	}
+	if err := r.registrationGroupRepo.UpdatePaymentStatus(groupID, string(status)); err != nil {
+		return nil, err
+	}
+	return func() {
+		for _, sendEmail := range sendEmails {
+			sendEmail()
+		}
+	}, nil
+}
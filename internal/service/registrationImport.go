@@ -0,0 +1,307 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// The columns of an import file are the ones of the registration export, matched by header
+// name in any order. The columns computed by the export, e.g. FullName or PaymentAmount, are ignored.
+const (
+	importColumnCategory           = "category"
+	importColumnRegistrationOption = "registrationoption"
+	importColumnNationality        = "nationality"
+	importColumnDoctorateDegree    = "doctoratedegree"
+	importColumnFirstName          = "firstname"
+	importColumnMiddleName         = "middlename"
+	importColumnLastName           = "lastname"
+	importColumnDateOfBirth        = "dateofbirth"
+	importColumnInstitution        = "institution"
+	importColumnEmail              = "email"
+	importColumnPhoneNumber        = "phonenumber"
+	importColumnSponsor            = "sponsor"
+	importColumnAttendGalaDinner   = "attendgaladinner"
+	importColumnAccompanyPersons   = "accompanypersons"
+	importColumnAddOns             = "addons"
+	importColumnPromoCode          = "promocode"
+)
+
+var (
+	// accompanyPersonPattern matches an accompanying person as exported: "First Middle Last (DOB: 1990-01-01)"
+	accompanyPersonPattern = regexp.MustCompile(`^(.*?)\s*\(DOB:\s*(.*)\)$`)
+	// addOnPattern matches an add-on as exported: "Gala Dinner x2"
+	addOnPattern = regexp.MustCompile(`^(.*?)\s+x(\d+)$`)
+	// dinnerCategories maps the "+ Gala Dinner" options, still exported for the registrations made
+	// with them, to the option now sold with the gala dinner add-on
+	dinnerCategories = map[model.RegistrationCategory]model.RegistrationCategory{
+		model.DoctorAndDinnerCategory:  model.DoctorCategory,
+		model.StudentAndDinnerCategory: model.StudentCategory,
+	}
+)
+
+// ImportRegistrations registers the attendees of an export-shaped file, the header being the
+// first record. Every row is validated like a registration request; valid rows are registered
+// and the others reported with their errors. Attendees are either marked paid offline or get a
+// payment link, as the request says.
+func (r registrationService) ImportRegistrations(
+	records [][]string, request dto.ImportRegistrationsRequest, operator, clientIP string,
+) (*dto.ImportRegistrationsResponse, error) {
+	if len(records) < 2 {
+		return nil, errs.ErrBadRequest.Reform("file has no attendees")
+	}
+	columns := map[string]int{}
+	for i, header := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, column := range []string{importColumnRegistrationOption, importColumnEmail, importColumnDoctorateDegree} {
+		if _, ok := columns[column]; !ok {
+			return nil, errs.ErrBadRequest.Reform("column %s is missing", column)
+		}
+	}
+	addOnCodes, err := r.importAddOnCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ImportRegistrationsResponse{Rows: []dto.ImportRowResult{}}
+	rowsByEmail := map[string]int{}
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		result := dto.ImportRowResult{Row: i + 2}
+		cell := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		req, rowErrors := parseImportRow(cell, addOnCodes)
+		result.Email = req.Email
+		email := strings.ToLower(req.Email)
+		if row, ok := rowsByEmail[email]; ok && email != "" {
+			rowErrors = append(rowErrors, fmt.Sprintf("email is also on row %d", row))
+		} else {
+			rowsByEmail[email] = result.Row
+		}
+		if len(rowErrors) == 0 {
+			if err := r.importRegistration(&result, req, request, operator, clientIP); err != nil {
+				rowErrors = append(rowErrors, importErrorMessage(err))
+			}
+		}
+		response.Total++
+		if len(rowErrors) > 0 {
+			result.Errors = rowErrors
+			response.Failed++
+		} else {
+			response.Imported++
+		}
+		response.Rows = append(response.Rows, result)
+	}
+	return response, nil
+}
+
+// importRegistration registers one valid row, or only checks it can be registered on a dry run.
+func (r registrationService) importRegistration(
+	result *dto.ImportRowResult, req dto.RegistrationRequest, request dto.ImportRegistrationsRequest, operator, clientIP string,
+) error {
+	oldReg, err := r.replaceableRegistration(req.Email)
+	if err != nil {
+		return err
+	}
+	if request.PaymentStatus == string(model.PaymentStatusDone) {
+		req.PaymentMethod = ""
+	}
+	reg, err := r.setupRegistration(req)
+	if err != nil {
+		return err
+	}
+	if request.DryRun {
+		result.PaymentStatus = request.PaymentStatus
+		return nil
+	}
+
+	if request.PaymentStatus == string(model.PaymentStatusPending) {
+		paymentURL, registrationID, err := r.Register(req, clientIP)
+		if err != nil {
+			return err
+		}
+		result.RegistrationID = registrationID
+		result.PaymentURL = paymentURL
+		result.PaymentStatus = string(model.PaymentStatusPending)
+		if paymentURL == "" {
+			result.PaymentStatus = string(model.PaymentStatusDone)
+		}
+		return nil
+	}
+
+	var replaced []model.Registration
+	if oldReg != nil {
+		if err := r.registrationRepo.Remove(oldReg.Id); err != nil {
+			return err
+		}
+		replaced = append(replaced, *oldReg)
+	}
+	reg.PaymentMethod = string(model.PaymentMethodOffline)
+	if err := r.createRegistration(reg); err != nil {
+		r.restoreRegistrations(replaced)
+		return err
+	}
+	amount, currency := amountDue(&reg)
+	err = r.settleOutsideOnePay(&reg, amount, currency, fmt.Sprintf("paid offline, imported by %s", operator), clientIP)
+	if err != nil {
+		return err
+	}
+	result.RegistrationID = reg.Id
+	result.PaymentStatus = string(model.PaymentStatusDone)
+	return nil
+}
+
+// parseImportRow builds the registration request of a row and validates it as the JSON binding would.
+func parseImportRow(cell func(column string) string, addOnCodes map[string]string) (dto.RegistrationRequest, []string) {
+	var rowErrors []string
+	req := dto.RegistrationRequest{
+		RegistrationCategory: cell(importColumnCategory),
+		RegistrationOption:   cell(importColumnRegistrationOption),
+		DoctorateDegree:      cell(importColumnDoctorateDegree),
+		FirstName:            cell(importColumnFirstName),
+		MiddleName:           cell(importColumnMiddleName),
+		LastName:             cell(importColumnLastName),
+		DateOfBirth:          cell(importColumnDateOfBirth),
+		Institution:          cell(importColumnInstitution),
+		Email:                cell(importColumnEmail),
+		PhoneNumber:          cell(importColumnPhoneNumber),
+		Sponsor:              cell(importColumnSponsor),
+		PromoCode:            cell(importColumnPromoCode),
+	}
+	if nationality := cell(importColumnNationality); nationality != "" {
+		req.Nationality = model.GetCountryCode(nationality)
+		if req.Nationality == "" {
+			rowErrors = append(rowErrors, fmt.Sprintf("unknown nationality %q", nationality))
+		}
+	}
+	switch strings.ToLower(cell(importColumnAttendGalaDinner)) {
+	case "", "no", "false", "0":
+	case "yes", "true", "1":
+		req.AttendGalaDinner = true
+	default:
+		rowErrors = append(rowErrors, fmt.Sprintf("AttendGalaDinner must be Yes or No, not %q", cell(importColumnAttendGalaDinner)))
+	}
+	if category, ok := dinnerCategories[model.RegistrationCategory(req.RegistrationOption)]; ok {
+		req.RegistrationOption = string(category)
+		req.AttendGalaDinner = true
+	}
+	if category, ok := dinnerCategories[model.RegistrationCategory(req.RegistrationCategory)]; ok {
+		req.RegistrationCategory = string(category)
+	}
+	for _, line := range splitLines(cell(importColumnAccompanyPersons)) {
+		person, err := parseAccompanyPerson(line)
+		if err != nil {
+			rowErrors = append(rowErrors, err.Error())
+			continue
+		}
+		req.AccompanyPersons = append(req.AccompanyPersons, person)
+	}
+	for _, line := range splitLines(cell(importColumnAddOns)) {
+		addOn, err := parseAddOn(line, addOnCodes)
+		if err != nil {
+			rowErrors = append(rowErrors, err.Error())
+			continue
+		}
+		// the gala dinners are ordered through AttendGalaDinner and AccompanyPersons
+		if addOn.Code == model.AddOnCodeGalaDinner {
+			continue
+		}
+		req.AddOns = append(req.AddOns, addOn)
+	}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		if fieldErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fieldError := range fieldErrors {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s fails the %s rule", fieldError.Field(), fieldError.Tag()))
+			}
+		} else {
+			rowErrors = append(rowErrors, err.Error())
+		}
+	}
+	return req, rowErrors
+}
+
+func parseAccompanyPerson(line string) (model.AccompanyPerson, error) {
+	name, dateOfBirth := line, ""
+	if match := accompanyPersonPattern.FindStringSubmatch(line); match != nil {
+		name, dateOfBirth = match[1], strings.TrimSpace(match[2])
+	}
+	names := strings.Fields(name)
+	if len(names) == 0 {
+		return model.AccompanyPerson{}, fmt.Errorf("accompanying person %q has no name", line)
+	}
+	person := model.AccompanyPerson{FirstName: names[0], DateOfBirth: dateOfBirth}
+	if len(names) > 1 {
+		person.LastName = names[len(names)-1]
+		person.MiddleName = strings.Join(names[1:len(names)-1], " ")
+	}
+	return person, nil
+}
+
+func parseAddOn(line string, addOnCodes map[string]string) (dto.AddOnRequest, error) {
+	name, quantity := line, 1
+	if match := addOnPattern.FindStringSubmatch(line); match != nil {
+		name = match[1]
+		quantity, _ = strconv.Atoi(match[2])
+	}
+	code, ok := addOnCodes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return dto.AddOnRequest{}, fmt.Errorf("unknown add-on %q", name)
+	}
+	return dto.AddOnRequest{Code: code, Quantity: quantity}, nil
+}
+
+// importAddOnCodes maps the lower-cased code and name of every add-on to its code.
+func (r registrationService) importAddOnCodes() (map[string]string, error) {
+	addOns, err := r.addOnSvc.ListAddOns()
+	if err != nil {
+		return nil, err
+	}
+	codes := map[string]string{}
+	for _, addOn := range addOns {
+		codes[strings.ToLower(addOn.Code)] = addOn.Code
+		codes[strings.ToLower(addOn.Name)] = addOn.Code
+	}
+	return codes, nil
+}
+
+func importErrorMessage(err error) string {
+	if appErr, ok := err.(errs.AppError); ok {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+func splitLines(value string) []string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
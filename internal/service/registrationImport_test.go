@@ -0,0 +1,24 @@
+package service
+
+import (
+	"ashno-onepay/internal/model"
+	"testing"
+)
+
+func TestParseImportRowMapsDinnerCategories(t *testing.T) {
+	row := map[string]string{
+		importColumnCategory:           string(model.StudentAndDinnerCategory),
+		importColumnRegistrationOption: string(model.StudentAndDinnerCategory),
+		importColumnAttendGalaDinner:   "No",
+	}
+	req, _ := parseImportRow(func(column string) string { return row[column] }, map[string]string{})
+	if req.RegistrationOption != string(model.StudentCategory) {
+		t.Fatalf("got option %q, want %q", req.RegistrationOption, model.StudentCategory)
+	}
+	if req.RegistrationCategory != string(model.StudentCategory) {
+		t.Fatalf("got category %q, want %q", req.RegistrationCategory, model.StudentCategory)
+	}
+	if !req.AttendGalaDinner {
+		t.Fatal("a + Gala Dinner option must order the gala dinner")
+	}
+}
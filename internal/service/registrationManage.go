@@ -0,0 +1,112 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"fmt"
+	"strings"
+)
+
+// GetManageableRegistration returns the registration made with email that its attendee may edit,
+// nil when there is none so the caller can answer the same whether or not the email is registered.
+func (r registrationService) GetManageableRegistration(email string) (*model.Registration, error) {
+	reg, err := r.registrationRepo.GetByEmail(strings.TrimSpace(email))
+	if err != nil || reg == nil {
+		return nil, err
+	}
+	if !isEditable(reg) {
+		return nil, nil
+	}
+	return reg, nil
+}
+
+// SendManageLink emails the attendee of a registration the magic link to edit it with.
+func (r registrationService) SendManageLink(reg *model.Registration, link string) {
+	go r.sendNotice(reg.Email, reg.FirstName, "Manage your registration",
+		fmt.Sprintf("Use this link within %d minutes to correct your name, institution or phone number: %s "+
+			"If you did not ask for it, you can ignore this email.", int(r.config.Manage.GetLinkTTL().Minutes()), link))
+}
+
+// GetManagedRegistration returns the registration a magic link was sent for, email being the
+// registration's when it was sent.
+func (r registrationService) GetManagedRegistration(registrationID, email string) (*model.Registration, error) {
+	if _, err := r.managedRegistration(registrationID, email); err != nil {
+		return nil, err
+	}
+	return r.GetRegistration(registrationID)
+}
+
+// UpdateDetails corrects the attendee details of a registration from a magic link. Only the given
+// fields that differ are changed, and each change is recorded on the audit log; the payment is left alone.
+func (r registrationService) UpdateDetails(registrationID, email string, request dto.UpdateDetailsRequest) (*model.Registration, error) {
+	reg, err := r.managedRegistration(registrationID, email)
+	if err != nil {
+		return nil, err
+	}
+	attendee := model.Attendee{
+		DoctorateDegree: reg.DoctorateDegree,
+		FirstName:       reg.FirstName,
+		MiddleName:      reg.MiddleName,
+		LastName:        reg.LastName,
+		DateOfBirth:     reg.DateOfBirth,
+		Institution:     reg.Institution,
+		Email:           reg.Email,
+		PhoneNumber:     reg.PhoneNumber,
+	}
+	var changes []string
+	edit := func(field string, value *string, current *string) {
+		if value == nil || strings.TrimSpace(*value) == *current {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s: %q to %q", field, *current, strings.TrimSpace(*value)))
+		*current = strings.TrimSpace(*value)
+	}
+	edit("doctorate_degree", request.DoctorateDegree, &attendee.DoctorateDegree)
+	edit("first_name", request.FirstName, &attendee.FirstName)
+	edit("middle_name", request.MiddleName, &attendee.MiddleName)
+	edit("last_name", request.LastName, &attendee.LastName)
+	edit("date_of_birth", request.DateOfBirth, &attendee.DateOfBirth)
+	edit("institution", request.Institution, &attendee.Institution)
+	edit("phone_number", request.PhoneNumber, &attendee.PhoneNumber)
+	if attendee.FirstName == "" || attendee.DoctorateDegree == "" {
+		return nil, errs.ErrInvalidArgument.Reform("first name and doctorate degree cannot be empty")
+	}
+	if len(changes) == 0 {
+		return r.GetRegistration(reg.Id)
+	}
+
+	if err := r.registrationRepo.UpdateDetails(reg.Id, attendee); err != nil {
+		return nil, err
+	}
+	r.audit(reg.Id, model.AuditActionDetailsEdited, reg.Email, strings.Join(changes, "; "))
+	go r.sendNotice(reg.Email, attendee.FirstName, "Your registration details were updated",
+		fmt.Sprintf("The following details of your registration were changed: %s. If you did not make this change, please contact us.",
+			strings.Join(changes, "; ")))
+	return r.GetRegistration(reg.Id)
+}
+
+// managedRegistration returns a registration its attendee may still edit with a link sent to email.
+func (r registrationService) managedRegistration(registrationID, email string) (*model.Registration, error) {
+	reg, err := r.registrationRepo.GetRegistration(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	// the registration was handed over to someone else since the link was sent
+	if !strings.EqualFold(reg.Email, email) {
+		return nil, errs.ErrInvalidSession.Reform("link is no longer valid, request a new one")
+	}
+	if !isEditable(reg) {
+		return nil, errs.ErrBadRequest.Reform("registration is %s", reg.PaymentStatus)
+	}
+	return reg, nil
+}
+
+// isEditable reports whether a registration is still going ahead, i.e. not cancelled or refunded.
+func isEditable(reg *model.Registration) bool {
+	switch model.PaymentStatus(reg.PaymentStatus) {
+	case model.PaymentStatusPending, model.PaymentStatusFail, model.PaymentStatusDone, model.PaymentStatusPartiallyRefunded:
+		return true
+	}
+	return false
+}
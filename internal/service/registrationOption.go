@@ -0,0 +1,231 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/repository"
+	"sync"
+)
+
+// RegistrationOptionService manages the prices sold at registration.
+type RegistrationOptionService interface {
+	ListOptions() ([]model.RegistrationOption, error)
+	CreateOption(request dto.CreateRegistrationOptionRequest) (*model.RegistrationOption, error)
+	UpdateOption(ID string, request dto.UpdateRegistrationOptionRequest) (*model.RegistrationOption, error)
+	SetOptionActive(ID string, active bool) (*model.RegistrationOption, error)
+	GetCatalogue() (*dto.RegistrationCatalogueResponse, error)
+	ListCapacities() ([]*model.CategoryCapacity, error)
+	ReplaceCapacities(request dto.ReplaceCategoryCapacitiesRequest) ([]*model.CategoryCapacity, error)
+}
+
+type registrationOptionService struct {
+	registrationOptionsRepo repository.RegistrationOptionRepository
+	categoryCapacityRepo    repository.CategoryCapacityRepository
+	periodSvc               PeriodService
+	addOnSvc                AddOnService
+}
+
+// GetCatalogue lists every option on sale grouped by category, with the registration periods
+// they belong to. Registration is reported closed when no period is active.
+func (s registrationOptionService) GetCatalogue() (*dto.RegistrationCatalogueResponse, error) {
+	periods, err := s.periodSvc.ListPeriods()
+	if err != nil {
+		return nil, err
+	}
+	currentPeriod, err := s.periodSvc.CurrentPeriod()
+	if err != nil && err != errs.ErrRegistrationClosed {
+		return nil, err
+	}
+	options, err := s.registrationOptionsRepo.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	subtype := ""
+	if currentPeriod != nil {
+		subtype = currentPeriod.Name
+	}
+	addOns, err := s.addOnSvc.GetCatalogue(subtype)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogue := &dto.RegistrationCatalogueResponse{
+		RegistrationOpen: currentPeriod != nil,
+		CurrentPeriod:    currentPeriod,
+		Periods:          periods,
+		Categories:       []dto.CatalogueCategory{},
+		AddOns:           addOns,
+	}
+	capacities, err := s.categoryCapacityRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	capacityIndex := map[string]*model.CategoryCapacity{}
+	for _, capacity := range capacities {
+		capacityIndex[capacity.Category] = capacity
+	}
+	categoryIndex := map[string]int{}
+	for _, option := range options {
+		i, ok := categoryIndex[option.Category]
+		if !ok {
+			i = len(catalogue.Categories)
+			categoryIndex[option.Category] = i
+			category := dto.CatalogueCategory{Category: option.Category, Remaining: -1}
+			if capacity, ok := capacityIndex[option.Category]; ok {
+				category.Capacity = capacity.Capacity
+				category.Remaining = capacity.Remaining()
+			}
+			catalogue.Categories = append(catalogue.Categories, category)
+		}
+		catalogue.Categories[i].Options = append(catalogue.Categories[i].Options, dto.CatalogueOption{
+			ID:      option.Id,
+			Subtype: option.Subtype,
+			FeeUSD:  option.FeeUSD,
+			FeeVND:  option.FeeVND,
+			Current: currentPeriod != nil && (option.Subtype == "" || option.Subtype == currentPeriod.Name),
+		})
+	}
+	return catalogue, nil
+}
+
+func (s registrationOptionService) ListOptions() ([]model.RegistrationOption, error) {
+	return s.registrationOptionsRepo.ListOption()
+}
+
+func (s registrationOptionService) CreateOption(request dto.CreateRegistrationOptionRequest) (*model.RegistrationOption, error) {
+	options, err := s.registrationOptionsRepo.ListOption()
+	if err != nil {
+		return nil, err
+	}
+	if !model.IsWholeUSD(request.FeeUSD) {
+		return nil, errs.ErrInvalidArgument.Reform("fee_usd must be whole dollars")
+	}
+	version := 1
+	for _, option := range options {
+		if option.Category != request.Category || option.Subtype != request.Subtype {
+			continue
+		}
+		if option.Active {
+			return nil, errs.ErrInvalidArgument.Reform("option %s %s already exists, update it instead", request.Category, request.Subtype)
+		}
+		if option.Version >= version {
+			version = option.Version + 1
+		}
+	}
+	return s.registrationOptionsRepo.Create(model.RegistrationOption{
+		Category: request.Category,
+		Subtype:  request.Subtype,
+		FeeUSD:   request.FeeUSD,
+		FeeVND:   request.FeeVND,
+		Active:   true,
+		Version:  version,
+	})
+}
+
+// UpdateOption changes the price of an option by creating its next version. The updated option
+// is deactivated but kept, so the registrations referencing it keep the price they were charged.
+func (s registrationOptionService) UpdateOption(ID string, request dto.UpdateRegistrationOptionRequest) (*model.RegistrationOption, error) {
+	option, err := s.getOption(ID)
+	if err != nil {
+		return nil, err
+	}
+	if !option.Active {
+		return nil, errs.ErrInvalidArgument.Reform("option %s is not active", ID)
+	}
+	if !model.IsWholeUSD(request.FeeUSD) {
+		return nil, errs.ErrInvalidArgument.Reform("fee_usd must be whole dollars")
+	}
+	return s.registrationOptionsRepo.CreateVersion(option.Id, model.RegistrationOption{
+		Category: option.Category,
+		Subtype:  option.Subtype,
+		FeeUSD:   request.FeeUSD,
+		FeeVND:   request.FeeVND,
+		Active:   true,
+		Version:  option.Version + 1,
+	})
+}
+
+// SetOptionActive puts an option on or off sale. Activating an option takes any other
+// version of the same category and subtype off sale.
+func (s registrationOptionService) SetOptionActive(ID string, active bool) (*model.RegistrationOption, error) {
+	if _, err := s.getOption(ID); err != nil {
+		return nil, err
+	}
+	var err error
+	if active {
+		err = s.registrationOptionsRepo.Activate(ID)
+	} else {
+		err = s.registrationOptionsRepo.Deactivate(ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getOption(ID)
+}
+
+// ListCapacities lists the category capacities with the seats reserved and attendees waiting for each.
+func (s registrationOptionService) ListCapacities() ([]*model.CategoryCapacity, error) {
+	return s.categoryCapacityRepo.List()
+}
+
+// ReplaceCapacities sets the capacity of every limited category; the categories left out are unlimited.
+// Lowering a capacity below the seats sold cancels no registration, it only stops new ones.
+func (s registrationOptionService) ReplaceCapacities(request dto.ReplaceCategoryCapacitiesRequest) ([]*model.CategoryCapacity, error) {
+	capacities := make([]model.CategoryCapacity, 0, len(request.Capacities))
+	categories := map[string]bool{}
+	for _, capacity := range request.Capacities {
+		if categories[capacity.Category] {
+			return nil, errs.ErrInvalidArgument.Reform("capacity of %s is given twice", capacity.Category)
+		}
+		categories[capacity.Category] = true
+		capacities = append(capacities, model.CategoryCapacity{
+			Category: capacity.Category,
+			Capacity: capacity.Capacity,
+		})
+	}
+	if err := s.categoryCapacityRepo.Replace(capacities); err != nil {
+		return nil, err
+	}
+	return s.categoryCapacityRepo.List()
+}
+
+func (s registrationOptionService) getOption(ID string) (*model.RegistrationOption, error) {
+	option, err := s.registrationOptionsRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if option == nil {
+		return nil, errs.ErrNotFound.Reform("option not found")
+	}
+	return option, nil
+}
+
+var registrationOptionServiceInstance RegistrationOptionService
+var registrationOptionServiceOnce sync.Once
+
+func GetRegistrationOptionServiceInstance(
+	registrationOptionsRepo repository.RegistrationOptionRepository,
+	categoryCapacityRepo repository.CategoryCapacityRepository,
+	periodSvc PeriodService,
+	addOnSvc AddOnService,
+) RegistrationOptionService {
+	registrationOptionServiceOnce.Do(func() {
+		registrationOptionServiceInstance = NewRegistrationOptionService(registrationOptionsRepo, categoryCapacityRepo, periodSvc, addOnSvc)
+	})
+	return registrationOptionServiceInstance
+}
+
+func NewRegistrationOptionService(
+	registrationOptionsRepo repository.RegistrationOptionRepository,
+	categoryCapacityRepo repository.CategoryCapacityRepository,
+	periodSvc PeriodService,
+	addOnSvc AddOnService,
+) RegistrationOptionService {
+	return &registrationOptionService{
+		registrationOptionsRepo: registrationOptionsRepo,
+		categoryCapacityRepo:    categoryCapacityRepo,
+		periodSvc:               periodSvc,
+		addOnSvc:                addOnSvc,
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"ashno-onepay/internal/model"
+	"ashno-onepay/internal/onepay"
+	"ashno-onepay/internal/repository"
+	"net/url"
+	"testing"
+)
+
+// unknownTransactions is a ledger that has recorded no payment attempt.
+type unknownTransactions struct {
+	repository.PaymentTransactionRepository
+}
+
+func (unknownTransactions) GetByMerchTxnRef(string, string) (*model.PaymentTransaction, error) {
+	return nil, nil
+}
+
+func TestVerifyCallbackWithOnlyDomesticProfile(t *testing.T) {
+	domestic := config.OnePay{MerchantID: "DOMESTIC", HashCode: "6D0870CDE5F24F34F3915FB0045120DB"}
+	r := registrationService{
+		paymentTransactionRepo: unknownTransactions{},
+		onePay: map[model.PaymentMethod]*onepay.Client{
+			model.PaymentMethodOnePayDomestic: onepay.NewClient(domestic),
+		},
+	}
+
+	query := url.Values{
+		"vpc_Merchant":    {"INTERNATIONAL"},
+		"vpc_MerchTxnRef": {"unknown"},
+		"vpc_OrderInfo":   {"REGunknown"},
+		"vpc_SecureHash":  {"00"},
+	}
+	if _, err := r.verifyCallback(query); err == nil || err.Error() != onepay.ErrInvalidSignature.Error() {
+		t.Fatalf("callback of an unconfigured merchant: got %v, want %v", err, onepay.ErrInvalidSignature)
+	}
+
+	query.Set("vpc_Merchant", domestic.MerchantID)
+	query.Set("vpc_TxnResponseCode", "0")
+	secureHash, err := onepay.Sign(flattenQuery(query), domestic.HashCode)
+	if err != nil {
+		t.Fatalf("sign callback: %v", err)
+	}
+	query.Set("vpc_SecureHash", secureHash)
+	result, err := r.verifyCallback(query)
+	if err != nil {
+		t.Fatalf("callback of the domestic merchant: %v", err)
+	}
+	if result.MerchTxnRef != "unknown" {
+		t.Fatalf("got MerchTxnRef %q, want %q", result.MerchTxnRef, "unknown")
+	}
+}
+
+func flattenQuery(query url.Values) map[string]string {
+	params := make(map[string]string, len(query))
+	for key := range query {
+		params[key] = query.Get(key)
+	}
+	return params
+}
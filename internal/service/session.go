@@ -0,0 +1,155 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/jwt"
+	"ashno-onepay/internal/model"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+func (s adminUserService) Login(email, password string) (*dto.LoginResponse, error) {
+	user, err := s.authenticate(email, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.DeleteExpired(time.Now()); err != nil {
+		log.Printf("failed to delete expired sessions: %v", err)
+	}
+	return s.startSession(user)
+}
+
+// Refresh renews a session. A refresh token used a second time means it leaked, so every session
+// of its user is ended.
+func (s adminUserService) Refresh(refreshToken string) (*dto.LoginResponse, error) {
+	token, err := s.sessionRepo.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if token == nil || token.ExpiresAt.Before(now) {
+		return nil, errs.ErrInvalidSession.Reform("refresh token is invalid or has expired, sign in again")
+	}
+	if token.RevokedAt != nil {
+		return nil, s.endReusedSession(token)
+	}
+	user, err := s.adminUserRepo.GetByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.Active {
+		return nil, errs.ErrInvalidSession.Reform("admin user is disabled")
+	}
+
+	renewed, err := s.startSession(user)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := s.sessionRepo.RevokeRefreshToken(token.Id, renewed.RefreshTokenID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// used concurrently by someone else
+		return nil, s.endReusedSession(token)
+	}
+	if err := s.sessionRepo.RevokeAccessToken(token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+		return nil, err
+	}
+	return renewed, nil
+}
+
+func (s adminUserService) endReusedSession(token *model.RefreshToken) error {
+	log.Printf("refresh token %s of admin user %s was reused, ending all their sessions", token.Id, token.UserID)
+	if err := s.LogoutEverywhere(token.UserID); err != nil {
+		return err
+	}
+	return errs.ErrInvalidSession.Reform("refresh token is invalid or has expired, sign in again")
+}
+
+func (s adminUserService) Logout(claims *jwt.UserClaims) error {
+	if err := s.sessionRepo.RevokeAccessToken(claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return err
+	}
+	token, err := s.sessionRepo.GetRefreshTokenByAccessTokenID(claims.Id)
+	if err != nil || token == nil {
+		return err
+	}
+	_, err = s.sessionRepo.RevokeRefreshToken(token.Id, "", time.Now())
+	return err
+}
+
+// LogoutEverywhere revokes the refresh tokens of an admin user and the access tokens issued with them.
+func (s adminUserService) LogoutEverywhere(userID string) error {
+	tokens, err := s.sessionRepo.RevokeUserRefreshTokens(userID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err := s.sessionRepo.RevokeAccessToken(token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s adminUserService) IsRevoked(tokenID string) (bool, error) {
+	return s.sessionRepo.IsAccessTokenRevoked(tokenID)
+}
+
+// startSession issues an access token and the refresh token renewing it.
+func (s adminUserService) startSession(user *model.AdminUser) (*dto.LoginResponse, error) {
+	now := time.Now()
+	accessToken := jwt.UserClaims{
+		StandardClaims: gojwt.StandardClaims{
+			Id:        uuid.New().String(),
+			Subject:   user.Id,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(s.config.Admin.GetAccessTokenTTL()).Unix(),
+		},
+		Email: user.Email,
+		Role:  jwt.Role(user.Role),
+	}
+	signed, err := s.issuer.Issue(context.Background(), &accessToken)
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(secret)
+	stored, err := s.sessionRepo.CreateRefreshToken(model.RefreshToken{
+		UserID:               user.Id,
+		TokenHash:            hashToken(refreshToken),
+		ExpiresAt:            now.Add(s.config.Admin.GetRefreshTokenTTL()).UTC(),
+		AccessTokenID:        accessToken.Id,
+		AccessTokenExpiresAt: time.Unix(accessToken.ExpiresAt, 0).UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{
+		Token:            signed,
+		ExpiresAt:        accessToken.ExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt.Unix(),
+		RefreshTokenID:   stored.Id,
+		User:             user,
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
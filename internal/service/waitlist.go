@@ -0,0 +1,250 @@
+package service
+
+import (
+	"ashno-onepay/internal/controller/dto"
+	errs "ashno-onepay/internal/errors"
+	"ashno-onepay/internal/model"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// JoinWaitlist queues an attendee for a seat of a sold-out category. The request is checked like a
+// registration and kept, so the registration can be made as asked once a seat frees up.
+func (r registrationService) JoinWaitlist(request dto.RegistrationRequest) (*model.WaitlistEntry, error) {
+	if _, err := r.replaceableRegistration(request.Email); err != nil {
+		return nil, err
+	}
+	active, err := r.waitlistRepo.GetActiveByEmail(request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, errs.ErrInvalidArgument.Reform("%s is already on the waitlist", request.Email)
+	}
+	reg, err := r.setupRegistration(request)
+	if err != nil {
+		return nil, err
+	}
+	category := reg.RegistrationOption.Category
+	capacity, err := r.categoryCapacityRepo.Get(category)
+	if err != nil {
+		return nil, err
+	}
+	if capacity == nil || capacity.Remaining() != 0 {
+		return nil, errs.ErrBadRequest.Reform("%s is not sold out, register instead", category)
+	}
+
+	content, err := json.Marshal(request)
+	if err != nil {
+		return nil, errs.ErrInternal.Wrap(err)
+	}
+	entry, err := r.waitlistRepo.Create(model.WaitlistEntry{
+		Category:  category,
+		Email:     request.Email,
+		FirstName: request.FirstName,
+		LastName:  request.LastName,
+		Status:    model.WaitlistStatusWaiting,
+		Request:   string(content),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entry.Position, err = r.waitlistRepo.Position(*entry); err != nil {
+		return nil, err
+	}
+	go r.sendNotice(entry.Email, entry.FirstName, "You are on the waitlist",
+		fmt.Sprintf("%s is sold out and you are number %d on the waitlist. We will email you a payment link as soon as a seat frees up.",
+			category, entry.Position))
+	return entry, nil
+}
+
+func (r registrationService) GetWaitlistEntry(ID string) (*model.WaitlistEntry, error) {
+	entry, err := r.waitlistRepo.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == model.WaitlistStatusWaiting {
+		if entry.Position, err = r.waitlistRepo.Position(*entry); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func (r registrationService) ListWaitlist(category, status string) ([]*model.WaitlistEntry, error) {
+	return r.waitlistRepo.List(category, status)
+}
+
+// InviteFromWaitlist offers the seats freed by failed, expired or cancelled registrations to the
+// waitlist, first come first served. Invitations not paid in time are expired first so their
+// seats are offered again.
+func (r registrationService) InviteFromWaitlist() error {
+	if err := r.settleInvitations(); err != nil {
+		return err
+	}
+	if _, err := r.periodSvc.CurrentPeriod(); err != nil {
+		if err == errs.ErrRegistrationClosed {
+			return nil
+		}
+		return err
+	}
+	capacities, err := r.categoryCapacityRepo.List()
+	if err != nil {
+		return err
+	}
+	for _, capacity := range capacities {
+		free := capacity.Capacity - capacity.Reserved
+		if capacity.Capacity == 0 || capacity.Waiting == 0 || free <= 0 {
+			continue
+		}
+		entries, err := r.waitlistRepo.ListWaiting(capacity.Category, free)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			err := r.invite(entry)
+			if err == nil {
+				continue
+			}
+			// a registrant took the seat first, the entry keeps its place for the next one
+			if isSoldOut(err) && !r.hasFreeSeat(capacity.Category) {
+				break
+			}
+			// an entry whose registration can no longer be made must not hold up the queue
+			log.Printf("Invite waitlist entry %s failed: %s", entry.Id, err.Error())
+			if _, err := r.waitlistRepo.UpdateStatus(entry.Id, model.WaitlistStatusWaiting, model.WaitlistStatusExpired); err != nil {
+				log.Printf("Expire waitlist entry %s failed: %s", entry.Id, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// settleInvitations marks the invited entries whose registration was paid as registered and the
+// ones that were not paid in time as expired; their registration stops holding the seat then too.
+func (r registrationService) settleInvitations() error {
+	entries, err := r.waitlistRepo.ListInvited()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		reg, err := r.registrationRepo.GetByEmail(entry.Email)
+		if err != nil {
+			return err
+		}
+		switch {
+		case reg != nil && isPaid(reg):
+			if _, err := r.waitlistRepo.UpdateStatus(entry.Id, model.WaitlistStatusInvited, model.WaitlistStatusRegistered); err != nil {
+				return err
+			}
+		case entry.ExpiresAt != nil && entry.ExpiresAt.Before(now):
+			expired, err := r.waitlistRepo.UpdateStatus(entry.Id, model.WaitlistStatusInvited, model.WaitlistStatusExpired)
+			if err != nil {
+				return err
+			}
+			if expired {
+				go r.sendNotice(entry.Email, entry.FirstName, "Your waitlist invitation has expired",
+					fmt.Sprintf("The seat for %s we offered you was not paid in time and has been offered to the next person on the waitlist.",
+						entry.Category))
+			}
+		}
+	}
+	return nil
+}
+
+// invite makes the registration a waiting entry asked for, holding its seat for the invitation
+// TTL, and emails the attendee the link to pay for it, which expires with the invitation.
+func (r registrationService) invite(entry *model.WaitlistEntry) error {
+	var request dto.RegistrationRequest
+	if err := json.Unmarshal([]byte(entry.Request), &request); err != nil {
+		return errs.ErrInternal.Wrap(err)
+	}
+	oldReg, err := r.replaceableRegistration(request.Email)
+	if err != nil {
+		return err
+	}
+	reg, err := r.setupRegistration(request)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	expiresAt := now.Add(r.config.Capacity.GetInvitationTTL())
+	reg.ReservedUntil = &expiresAt
+	var paymentURL string
+	var transaction model.PaymentTransaction
+	paysOnePay := model.PaymentMethod(reg.PaymentMethod).IsOnePay()
+	if !nothingToPay(reg) && paysOnePay {
+		feeUSD, feeVND := reg.Fee()
+		paymentURL, transaction, err = r.newPaymentUntil(registrationPayer(&reg), model.OrderTypeRegistration,
+			reg.Id, RandomString(16), feeUSD, feeVND, "", expiresAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the email is only free for the new registration once the old one is gone, which is put
+	// back when the invitation cannot be made
+	var replaced []model.Registration
+	if oldReg != nil {
+		if err := r.registrationRepo.Remove(oldReg.Id); err != nil {
+			return err
+		}
+		replaced = append(replaced, *oldReg)
+	}
+	// the seat was freed for the waitlist, the attendees still waiting have no claim on it
+	if err := r.storeRegistration(reg, 0); err != nil {
+		r.restoreRegistrations(replaced)
+		return err
+	}
+	if paysOnePay && !nothingToPay(reg) {
+		if _, err := r.paymentTransactionRepo.Create(transaction); err != nil {
+			r.discardRegistration(reg.Id)
+			r.restoreRegistrations(replaced)
+			return err
+		}
+	}
+	invited, err := r.waitlistRepo.Invite(entry.Id, reg.Id, now, expiresAt)
+	if err != nil || !invited {
+		if expireErr := r.paymentTransactionRepo.ExpirePending(reg.Id, "waitlist invitation not made"); expireErr != nil {
+			log.Printf("Expire payment of registration %s failed: %s", reg.Id, expireErr.Error())
+		}
+		r.discardRegistration(reg.Id)
+		r.restoreRegistrations(replaced)
+		return err
+	}
+
+	if nothingToPay(reg) {
+		if err := r.completeWithoutPayment(&reg, ""); err != nil {
+			return err
+		}
+		_, err := r.waitlistRepo.UpdateStatus(entry.Id, model.WaitlistStatusInvited, model.WaitlistStatusRegistered)
+		return err
+	}
+	// bank transfers and cash are paid as the registration page tells
+	if !paysOnePay {
+		paymentURL = fmt.Sprintf("%s/%s", r.config.OnePay.ReturnURL, reg.Id)
+	}
+	go r.sendNotice(entry.Email, entry.FirstName, "A seat is available for you",
+		fmt.Sprintf("A seat for %s has freed up and is held for you until %s UTC. Complete your payment at %s before then, "+
+			"after which the seat is offered to the next person on the waitlist.",
+			entry.Category, expiresAt.Format("2006-01-02 15:04"), paymentURL))
+	return nil
+}
+
+// hasFreeSeat reports whether a category has a seat neither paid nor held, waitlist aside.
+func (r registrationService) hasFreeSeat(category string) bool {
+	capacity, err := r.categoryCapacityRepo.Get(category)
+	if err != nil {
+		log.Printf("Get capacity of %s failed: %s", category, err.Error())
+		return false
+	}
+	return capacity == nil || capacity.Capacity == 0 || capacity.Reserved < capacity.Capacity
+}
+
+func isSoldOut(err error) bool {
+	appErr, ok := err.(errs.AppError)
+	return ok && appErr.Code == errs.ErrSoldOut.Code
+}
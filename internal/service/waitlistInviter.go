@@ -0,0 +1,37 @@
+package service
+
+import (
+	"ashno-onepay/internal/config"
+	"context"
+	"log"
+	"time"
+)
+
+// WaitlistInviter periodically offers the seats that freed up to the waitlist.
+type WaitlistInviter struct {
+	registrationSvc RegistrationService
+	config          *config.Config
+}
+
+func NewWaitlistInviter(registrationSvc RegistrationService, config *config.Config) *WaitlistInviter {
+	return &WaitlistInviter{
+		registrationSvc: registrationSvc,
+		config:          config,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *WaitlistInviter) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Capacity.GetWaitlistInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.registrationSvc.InviteFromWaitlist(); err != nil {
+				log.Printf("Invite from waitlist failed: %s", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package vietqr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NAPAS identifies VietQR transfers to a bank account inside the EMVCo merchant account information.
+const (
+	napasGUID                = "A000000727"
+	serviceToAccount         = "QRIBFTTA"
+	currencyVND              = "704"
+	countryVietNam           = "VN"
+	pointOfInitiationDynamic = "12" // dynamic QR, the amount is fixed
+)
+
+// MaxMemoLength is the longest transfer memo banking apps accept in a VietQR payload.
+const MaxMemoLength = 25
+
+// Transfer is a bank transfer a VietQR payload asks the payer's banking app to make.
+type Transfer struct {
+	BankBIN       string // 6-digit NAPAS bank identification number
+	AccountNumber string
+	Amount        int64 // VND, 0 lets the payer type it
+	Memo          string
+}
+
+// Payload builds the EMVCo QR payload of a transfer as specified by NAPAS for VietQR.
+// Every field is an ID, a 2-digit length and a value; the payload ends with its CRC.
+func Payload(t Transfer) (string, error) {
+	if t.BankBIN == "" || t.AccountNumber == "" {
+		return "", fmt.Errorf("bank BIN and account number are required")
+	}
+	if len(t.Memo) > MaxMemoLength {
+		return "", fmt.Errorf("memo is longer than %d characters", MaxMemoLength)
+	}
+	beneficiary := field("00", t.BankBIN) + field("01", t.AccountNumber)
+	merchantAccount := field("00", napasGUID) + field("01", beneficiary) + field("02", serviceToAccount)
+
+	var b strings.Builder
+	b.WriteString(field("00", "01"))
+	b.WriteString(field("01", pointOfInitiationDynamic))
+	b.WriteString(field("38", merchantAccount))
+	b.WriteString(field("53", currencyVND))
+	if t.Amount > 0 {
+		b.WriteString(field("54", strconv.FormatInt(t.Amount, 10)))
+	}
+	b.WriteString(field("58", countryVietNam))
+	if t.Memo != "" {
+		b.WriteString(field("62", field("08", t.Memo)))
+	}
+	b.WriteString("6304")
+	return b.String() + fmt.Sprintf("%04X", crc16(b.String())), nil
+}
+
+func field(ID, value string) string {
+	return fmt.Sprintf("%s%02d%s", ID, len(value), value)
+}
+
+// crc16 is CRC-16/CCITT-FALSE, the checksum EMVCo requires over the payload up to the CRC length.
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}